@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"ms/internal/client"
+	"ms/internal/config"
+	"ms/internal/keys"
+	"ms/internal/service"
+	"ms/pkg/utils"
+	"os"
+)
+
+// fundParams проверяет настройки fund: нужна цель (targetBalance или amount) и предел расхода.
+func fundParams(cfg *config.AppConfig) (service.FundParams, error) {
+	fund := cfg.Fund
+
+	if fund.TargetBalance.Sign() == 0 && fund.Amount.Sign() == 0 {
+		return service.FundParams{}, errors.New("set fund.targetBalance or fund.amount in config.yaml")
+	}
+	if fund.SpendCap.Sign() == 0 {
+		return service.FundParams{}, errors.New("set fund.spendCap in config.yaml")
+	}
+
+	return service.FundParams{
+		TargetBalance: fund.TargetBalance,
+		Amount:        fund.Amount,
+		SpendCap:      fund.SpendCap,
+		MaxInFlight:   fund.MaxInFlight,
+	}, nil
+}
+
+// loadTreasury загружает ключ казначейства из keystore файла или переменной окружения.
+func loadTreasury(cfg *config.AppConfig) (client.Signer, error) {
+	if cfg.Fund.TreasuryKeystore != "" {
+		pass, err := keys.Passphrase(cfg.Keys.PassphraseEnv, "Пароль keystore казначейства: ", false)
+		if err != nil {
+			return nil, err
+		}
+		return client.NewKeystoreSigner(cfg.Fund.TreasuryKeystore, pass)
+	}
+
+	hexKey := os.Getenv(cfg.Fund.TreasuryKeyEnv)
+	if hexKey == "" {
+		return nil, fmt.Errorf("treasury key is not set: export %s or set fund.treasuryKeystore", cfg.Fund.TreasuryKeyEnv)
+	}
+
+	key, err := utils.ParsePrivateKey(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid treasury key in %s: %w", cfg.Fund.TreasuryKeyEnv, err)
+	}
+
+	return client.NewKeySigner(key), nil
+}
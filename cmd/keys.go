@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"ms/internal/config"
+	"ms/internal/keys"
+	"ms/internal/models"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultEncryptedFile = "private_keys.enc"
+	defaultMnemonicFile  = "mnemonic.enc"
+)
+
+// loadAccounts загружает аккаунты из внешнего сервиса подписи, HD мнемоники, каталога keystore,
+// зашифрованного файла ключей или, если ни один из них не задан, из открытого privateKeysFile.
+func loadAccounts(ctx context.Context, cfg *config.AppConfig) ([]models.Account, models.LoadReport, error) {
+	hd := cfg.Keys.HD
+
+	switch {
+	case cfg.Keys.RemoteSigner.URL != "":
+		addresses := make([]common.Address, 0, len(cfg.Keys.RemoteSigner.Addresses))
+		for _, addr := range cfg.Keys.RemoteSigner.Addresses {
+			addresses = append(addresses, common.HexToAddress(addr))
+		}
+		return models.LoadAccountsFromRemoteSigner(ctx, cfg.Keys.RemoteSigner.URL, addresses)
+	case hd.MnemonicEnv != "" || hd.MnemonicFile != "":
+		mnemonic, err := loadMnemonic(cfg)
+		if err != nil {
+			return nil, models.LoadReport{Source: "mnemonic"}, err
+		}
+		return models.LoadAccountsFromMnemonic(mnemonic, hd.Path, hd.From, hd.To)
+	case cfg.Keys.KeystoreDir != "":
+		pass, err := keys.Passphrase(cfg.Keys.PassphraseEnv, "Пароль keystore: ", false)
+		if err != nil {
+			return nil, models.LoadReport{Source: cfg.Keys.KeystoreDir}, err
+		}
+		return models.LoadAccountsFromKeystore(cfg.Keys.KeystoreDir, pass)
+	case cfg.Keys.EncryptedFile != "":
+		pass, err := keys.Passphrase(cfg.Keys.PassphraseEnv, "Пароль файла ключей: ", false)
+		if err != nil {
+			return nil, models.LoadReport{Source: cfg.Keys.EncryptedFile}, err
+		}
+		return models.LoadAccountsFromBundle(cfg.Keys.EncryptedFile, pass)
+	default:
+		return models.LoadAccountsFromFile(cfg.PrivateKeysFile)
+	}
+}
+
+func loadMnemonic(cfg *config.AppConfig) (string, error) {
+	hd := cfg.Keys.HD
+
+	if hd.MnemonicEnv != "" {
+		mnemonic := os.Getenv(hd.MnemonicEnv)
+		if mnemonic == "" {
+			return "", fmt.Errorf("переменная окружения %s с мнемоникой не задана", hd.MnemonicEnv)
+		}
+		return mnemonic, nil
+	}
+
+	pass, err := keys.Passphrase(cfg.Keys.PassphraseEnv, "Пароль файла мнемоники: ", false)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := keys.ReadBundle(hd.MnemonicFile, pass)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// runKeys выполняет команды управления ключами. Сейчас поддерживается только
+// keys encrypt — перевод открытого файла ключей (или мнемоники с --mnemonic) в зашифрованный.
+func runKeys(cfg *config.AppConfig, args []string) {
+	if len(args) == 0 || args[0] != "encrypt" {
+		log.Fatalf("unknown keys command, expected: keys encrypt [--mnemonic] [--in file] [--out file] [--kdf scrypt|argon2id]")
+	}
+
+	flags := flag.NewFlagSet("keys encrypt", flag.ExitOnError)
+	isMnemonic := flags.Bool("mnemonic", false, "input file contains a BIP-39 mnemonic instead of private keys")
+	inPath := flags.String("in", "", "plaintext input file (default: privateKeysFile)")
+	outPath := flags.String("out", "", "encrypted file to create (default: keys.encryptedFile or keys.hd.mnemonicFile)")
+	kdf := flags.String("kdf", keys.KDFScrypt, "key derivation function: scrypt or argon2id")
+	flags.Parse(args[1:])
+
+	if *inPath == "" {
+		if *isMnemonic {
+			log.Fatalf("--in is required with --mnemonic")
+		}
+		*inPath = cfg.PrivateKeysFile
+	}
+	if *outPath == "" {
+		*outPath = defaultOutPath(cfg, *isMnemonic)
+	}
+
+	if _, err := os.Stat(*outPath); err == nil {
+		log.Fatalf("%s already exists, refusing to overwrite", *outPath)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("failed to check %s: %v", *outPath, err)
+	}
+
+	plaintext, err := os.ReadFile(*inPath)
+	if err != nil {
+		log.Fatalf("failed to read plaintext file: %v", err)
+	}
+
+	// Проверяем содержимое до того как шифровать файл
+	what := "mnemonic"
+	if *isMnemonic {
+		if _, err := keys.NewHDWallet(string(plaintext), ""); err != nil {
+			log.Fatalf("invalid mnemonic: %v", err)
+		}
+	} else {
+		accounts, report, err := models.LoadAccountsFromFile(*inPath)
+		report.Log()
+		if err == nil {
+			err = report.Err()
+		}
+		if err != nil {
+			log.Fatalf("failed to read plaintext keys: %v", err)
+		}
+		what = fmt.Sprintf("%d keys", len(accounts))
+	}
+
+	pass, err := keys.Passphrase(cfg.Keys.PassphraseEnv, "Новый пароль файла ключей: ", true)
+	if err != nil {
+		log.Fatalf("failed to get passphrase: %v", err)
+	}
+
+	if err := keys.WriteBundle(*outPath, plaintext, pass, *kdf); err != nil {
+		log.Fatalf("failed to encrypt keys: %v", err)
+	}
+
+	decrypted, err := keys.ReadBundle(*outPath, pass)
+	if err == nil && !bytes.Equal(decrypted, plaintext) {
+		err = errors.New("decrypted content differs from the input")
+	}
+	if err != nil {
+		os.Remove(*outPath)
+		log.Fatalf("encrypted file verification failed: %v", err)
+	}
+
+	setting := "keys.encryptedFile"
+	if *isMnemonic {
+		setting = "keys.hd.mnemonicFile"
+	}
+
+	log.Printf("[INFO] Encrypted %s from %s to %s", what, *inPath, *outPath)
+	log.Printf("[INFO] Set %s: %s in config.yaml and delete %s", setting, *outPath, *inPath)
+}
+
+func defaultOutPath(cfg *config.AppConfig, isMnemonic bool) string {
+	switch {
+	case isMnemonic && cfg.Keys.HD.MnemonicFile != "":
+		return cfg.Keys.HD.MnemonicFile
+	case isMnemonic:
+		return defaultMnemonicFile
+	case cfg.Keys.EncryptedFile != "":
+		return cfg.Keys.EncryptedFile
+	default:
+		return defaultEncryptedFile
+	}
+}
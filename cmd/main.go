@@ -2,20 +2,41 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"ms/internal/client"
+	consts "ms/internal/client/consts"
 	"ms/internal/config"
-	"ms/internal/models"
+	"ms/internal/journal"
 	"ms/internal/service"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
 	"time"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func main() {
+	// Режим работы задается первым аргументом: stake (по умолчанию), unstake, compound, claim, positions, fund, sweep или keys
+	command, args := "stake", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "simulate planned stakes and transfers without broadcasting")
+	newRun := flags.Bool("new-run", false, "start a new run in the journal instead of resuming the last one")
+	flags.Parse(args)
+
+	// Симуляция реализована только для stake, fund и sweep: в остальных режимах транзакции ушли бы в сеть
+	if *dryRun && command != "stake" && command != "fund" && command != "sweep" {
+		log.Fatalf("--dry-run is not supported by %s, only by stake, fund and sweep", command)
+	}
+
 	// Создаем контекст с возможностью отмены
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -40,24 +61,121 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	ethClient, err := client.NewEthClient(ctx, cfg.RPCString)
+	if command == "keys" {
+		runKeys(cfg, flags.Args())
+		return
+	}
+
+	consts.SetInit()
+
+	clientOpts := clientOptions(cfg)
+
+	endpoints := make([]client.Endpoint, 0, len(cfg.Endpoints()))
+	for _, rpc := range cfg.Endpoints() {
+		endpoints = append(endpoints, client.Endpoint{URL: rpc.URL, Priority: rpc.Priority})
+	}
+
+	ethClient, err := client.NewEthClient(ctx, endpoints, clientOpts)
 	if err != nil {
 		log.Fatalf("failed to init eth client: %v", err)
 	}
 
-	accounts, err := models.LoadAccountsFromFile(cfg.PrivateKeysFile)
+	accounts, report, err := loadAccounts(ctx, cfg)
+	report.Log()
+	if err == nil && cfg.Keys.Mode == config.KeysModeStrict {
+		err = report.Err()
+	}
 	if err != nil {
 		log.Fatalf("failed to init accounts: %v", err)
 	}
 
-	srv := service.NewStaker(ctx, ethClient)
+	// Журнал ведется только для реального stake, чтобы прерванный запуск можно было продолжить
+	var runJournal *journal.Journal
+	if command == "stake" && !*dryRun {
+		runJournal, err = journal.Open(cfg.JournalFile, *newRun)
+		if err != nil {
+			log.Fatalf("failed to open run journal: %v", err)
+		}
+		defer runJournal.Close()
 
-	srv.Start(ctx, service.RunParams{
-		Stake:           service.Range{Min: cfg.Stake.Min, Max: cfg.Stake.Max},
-		Delay:           service.Range{Min: cfg.Delay.Min, Max: cfg.Delay.Max},
-		Validators:      cfg.Validators,
-		ContractAddress: cfg.ContractAddress,
-	}, accounts)
+		log.Printf("[INFO] Run journal %s, run %s", cfg.JournalFile, runJournal.RunID())
+	}
+
+	srv := service.NewStaker(ctx, ethClient, runJournal)
+
+	overrides, err := accountOverrides(cfg)
+	if err != nil {
+		log.Fatalf("failed to load accounts file: %v", err)
+	}
+
+	params := service.RunParams{
+		Stake: service.AmountRange{Min: cfg.Stake.Min, Max: cfg.Stake.Max},
+		Sizing: service.Sizing{
+			Strategy:    service.SizingStrategy(cfg.Stake.Strategy),
+			Amount:      cfg.Stake.Amount,
+			Percent:     cfg.Stake.Percent,
+			Reserve:     cfg.Stake.Reserve,
+			Granularity: cfg.Stake.Granularity,
+		},
+		Delay:      service.Range{Min: cfg.Delay.Min, Max: cfg.Delay.Max},
+		Validators: cfg.Validators.IDs,
+		Selection: service.ValidatorSelection{
+			Strategy: service.SelectionStrategy(cfg.ValidatorSelection.Strategy),
+			Weights:  cfg.ValidatorSelection.Weights,
+		},
+		ContractAddress:      cfg.ContractAddress,
+		WithdrawPollInterval: cfg.Unstake.PollInterval,
+		DryRun:               *dryRun,
+		Overrides:            overrides,
+	}
+
+	switch command {
+	case "stake":
+		if params.Validators, err = resolveValidators(ctx, cfg, ethClient); err != nil {
+			log.Fatalf("failed to resolve validators: %v", err)
+		}
+		if err := srv.CheckValidators(ctx, params); err != nil {
+			log.Fatalf("failed to validate validators: %v", err)
+		}
+		srv.Start(ctx, params, accounts)
+	case "unstake":
+		srv.Unstake(ctx, params, accounts)
+	case "compound":
+		srv.Compound(ctx, params, accounts)
+	case "claim":
+		srv.ClaimRewards(ctx, params, accounts)
+	case "fund":
+		fund, err := fundParams(cfg)
+		if err != nil {
+			log.Fatalf("invalid fund settings: %v", err)
+		}
+		treasury, err := loadTreasury(cfg)
+		if err != nil {
+			log.Fatalf("failed to load treasury key: %v", err)
+		}
+		srv.Fund(ctx, params, fund, treasury, accounts)
+	case "sweep":
+		if cfg.Sweep.Treasury == "" {
+			log.Fatalf("set sweep.treasury in config.yaml")
+		}
+		sweep := service.SweepParams{
+			Treasury:  common.HexToAddress(cfg.Sweep.Treasury),
+			MinAmount: cfg.Sweep.MinAmount,
+			Exclude:   cfg.Sweep.Exclude,
+			Delay:     service.Range{Min: cfg.Sweep.Delay.Min, Max: cfg.Sweep.Delay.Max},
+		}
+		if err := srv.Sweep(ctx, params, sweep, accounts, os.Stdout); err != nil {
+			log.Fatalf("failed to print sweep report: %v", err)
+		}
+		return
+	case "positions":
+		if err := srv.Positions(ctx, params, accounts, os.Stdout); err != nil {
+			log.Fatalf("failed to print positions: %v", err)
+		}
+		return
+	default:
+		log.Fatalf("unknown command %q, expected stake, unstake, compound, claim, positions, fund, sweep or keys", command)
+	}
 
 	log.Println("[INFO] Ожидание завершения всех транзакций...")
 
@@ -82,5 +200,90 @@ func main() {
 		}
 	}
 
+	if command == "stake" {
+		for _, r := range srv.RejectedValidators() {
+			log.Printf("[WARN] Rejected in this run: %v", r)
+		}
+	}
+
 	log.Println("[INFO] Программа завершается.")
 }
+
+// accountOverrides загружает файл настроек аккаунтов; ключи — адреса и метки в нижнем регистре.
+func accountOverrides(cfg *config.AppConfig) (map[string]service.AccountOverride, error) {
+	if cfg.AccountsFile == "" {
+		return nil, nil
+	}
+
+	list, err := config.LoadAccountOverrides(cfg.AccountsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[string]service.AccountOverride, len(list))
+	for _, o := range list {
+		override := service.AccountOverride{
+			Validators: o.Validators,
+			Enabled:    o.Enabled,
+		}
+		if o.Stake != nil {
+			override.Stake = &service.AmountRange{Min: o.Stake.Min, Max: o.Stake.Max}
+		}
+		if o.Delay != nil {
+			override.Delay = &service.Range{Min: o.Delay.Min, Max: o.Delay.Max}
+		}
+
+		key := o.Match
+		if common.IsHexAddress(key) {
+			key = common.HexToAddress(key).Hex()
+		}
+		overrides[strings.ToLower(key)] = override
+	}
+
+	log.Printf("[INFO] Loaded %d account overrides from %s", len(overrides), cfg.AccountsFile)
+
+	return overrides, nil
+}
+
+func clientOptions(cfg *config.AppConfig) client.Options {
+	opts := client.Options{
+		Pool: client.PoolOptions{
+			HealthCheckInterval: time.Duration(cfg.RPCPool.HealthCheckInterval) * time.Second,
+			MaxBlockLag:         cfg.RPCPool.MaxBlockLag,
+			ReadQuorum:          cfg.RPCPool.ReadQuorum,
+			BroadcastAll:        cfg.RPCPool.BroadcastAll,
+			RequestsPerSecond:   cfg.RPCPool.RequestsPerSecond,
+			Burst:               cfg.RPCPool.Burst,
+			CallTimeout:         time.Duration(cfg.RPCPool.CallTimeout * float32(time.Second)),
+			SendTimeout:         time.Duration(cfg.RPCPool.SendTimeout * float32(time.Second)),
+			Retry: client.RetryPolicy{
+				MaxAttempts: cfg.RPCPool.Retry.MaxAttempts,
+				BaseDelay:   time.Duration(cfg.RPCPool.Retry.BaseDelay * float32(time.Second)),
+				MaxDelay:    time.Duration(cfg.RPCPool.Retry.MaxDelay * float32(time.Second)),
+			},
+		},
+		Gas: client.GasPolicy{
+			BaseFeeMultiplier:     cfg.Gas.BaseFeeMultiplier,
+			TipPercentile:         cfg.Gas.TipPercentile,
+			FeeHistoryBlocks:      cfg.Gas.FeeHistoryBlocks,
+			GasLimitBufferPercent: cfg.Gas.GasLimitBuffer,
+			WaitInterval:          time.Duration(cfg.Gas.WaitInterval) * time.Second,
+		},
+		Replacement: client.ReplacementPolicy{
+			StuckBlocks: cfg.Replacement.StuckBlocks,
+			BumpPercent: cfg.Replacement.BumpPercent,
+		},
+	}
+
+	if cfg.Gas.Tip.Sign() > 0 {
+		opts.Gas.TipOverride = cfg.Gas.Tip.Wei()
+	}
+	if cfg.Gas.MaxFeePerGas.Sign() > 0 {
+		opts.Gas.MaxFeePerGas = cfg.Gas.MaxFeePerGas.Wei()
+	}
+	if cfg.Replacement.MaxFeePerGas.Sign() > 0 {
+		opts.Replacement.MaxFeePerGas = cfg.Replacement.MaxFeePerGas.Wei()
+	}
+
+	return opts
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"ms/internal/client"
+	"ms/internal/config"
+	"ms/internal/discovery"
+	"ms/pkg/utils"
+	"strconv"
+	"time"
+)
+
+// Комиссия в стейкинг-контракте хранится с 18 знаками (1e18 = 100%), в процентах — с 16.
+const commissionPercentDecimals = 16
+
+// resolveValidators возвращает список валидаторов для стейка: явный список из конфига или
+// активные валидаторы контракта, отобранные фильтром (validators: auto или фильтр).
+func resolveValidators(ctx context.Context, cfg *config.AppConfig, ethClient *client.EthClient) ([]uint64, error) {
+	if !cfg.Validators.Auto {
+		return cfg.Validators.IDs, nil
+	}
+
+	d := discovery.New(ethClient, cfg.ContractAddress, cfg.ValidatorDiscovery.CacheFile,
+		time.Duration(cfg.ValidatorDiscovery.TTL*float32(time.Second)))
+
+	validators, err := d.Validators(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := validatorFilter(cfg.Validators.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := filter.Apply(validators)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("none of %d active validators match the validators filter", len(validators))
+	}
+
+	log.Printf("[INFO] %d of %d active validators match the validators filter", len(ids), len(validators))
+
+	return ids, nil
+}
+
+func validatorFilter(f config.ValidatorFilter) (discovery.Filter, error) {
+	filter := discovery.Filter{Exclude: f.Exclude}
+
+	if f.MinStake.Sign() > 0 {
+		filter.MinStake = f.MinStake.Wei()
+	}
+
+	// 0 — тоже ограничение: только валидаторы без комиссии
+	if f.MaxCommission != nil {
+		maxCommission, err := utils.ParseUnits(strconv.FormatFloat(float64(*f.MaxCommission), 'f', -1, 32), commissionPercentDecimals)
+		if err != nil {
+			return discovery.Filter{}, fmt.Errorf("invalid maxCommission: %w", err)
+		}
+		filter.MaxCommission = maxCommission
+	}
+
+	return filter, nil
+}
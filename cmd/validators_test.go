@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math/big"
+	"ms/internal/config"
+	"ms/internal/discovery"
+	"testing"
+)
+
+func TestValidatorFilterMaxCommission(t *testing.T) {
+	// Комиссии 0%, 5% и 10% в единицах контракта
+	validators := []discovery.Validator{
+		{ID: 1, Commission: big.NewInt(0)},
+		{ID: 2, Commission: new(big.Int).Mul(big.NewInt(5), big.NewInt(1e16))},
+		{ID: 3, Commission: new(big.Int).Mul(big.NewInt(10), big.NewInt(1e16))},
+	}
+
+	percent := func(v float32) *float32 { return &v }
+
+	tests := []struct {
+		name          string
+		maxCommission *float32
+		want          []uint64
+	}{
+		{"not set", nil, []uint64{1, 2, 3}},
+		{"zero keeps only validators without commission", percent(0), []uint64{1}},
+		{"fractional", percent(5.5), []uint64{1, 2}},
+		{"inclusive", percent(10), []uint64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		filter, err := validatorFilter(config.ValidatorFilter{MaxCommission: tt.maxCommission})
+		if err != nil {
+			t.Fatalf("%s: validatorFilter: %v", tt.name, err)
+		}
+
+		got := filter.Apply(validators)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: Apply = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: Apply = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -8,8 +8,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-func (c *EthClient) BalanceCheck(owner common.Address) (*big.Int, error) {
-	balance, err := c.client.BalanceAt(context.Background(), owner, nil)
+func (c *EthClient) BalanceCheck(ctx context.Context, owner common.Address) (*big.Int, error) {
+	balance, err := c.client.BalanceAt(ctx, owner, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get native coin balance: %v", err)
 	}
@@ -7,50 +7,75 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
 )
 
-func (c *EthClient) CallCA(toCA common.Address, data []byte) ([]byte, error) {
+func (c *EthClient) CallCA(ctx context.Context, toCA common.Address, data []byte) ([]byte, error) {
 	callMsg := ethereum.CallMsg{
 		To:   &toCA,
 		Data: data,
 	}
 
-	return c.client.CallContract(context.Background(), callMsg, nil)
+	return c.client.CallContract(ctx, callMsg, nil)
 }
 
-func (c *EthClient) GetNonce(address common.Address) uint64 {
-	nonce, err := c.client.PendingNonceAt(context.Background(), address)
+func (c *EthClient) GetNonce(ctx context.Context, address common.Address) (uint64, error) {
+	nonce, err := c.client.PendingNonceAt(ctx, address)
 	if err != nil {
-		return 0
+		return 0, fmt.Errorf("failed to get nonce: %w", err)
 	}
-	return nonce
+	return nonce, nil
 }
 
-func (c *EthClient) GetChainID() (int64, error) {
-	chainID, err := c.client.NetworkID(context.Background())
+func (c *EthClient) GetChainID(ctx context.Context) (int64, error) {
+	chainID, err := c.client.NetworkID(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get ChainID: %w", err)
 	}
 	return chainID.Int64(), nil
 }
 
-func (c *EthClient) GetGasValues(msg ethereum.CallMsg) (uint64, *big.Int, *big.Int, error) {
-	header, err := c.client.HeaderByNumber(context.Background(), nil)
+func (c *EthClient) GetGasValues(ctx context.Context, msg ethereum.CallMsg) (uint64, *big.Int, *big.Int, error) {
+	header, err := c.client.HeaderByNumber(ctx, nil)
 	if err != nil {
 		return 0, nil, nil, fmt.Errorf("ошибка получения заголовка блока: %w", err)
 	}
 
-	maxPriorityFeePerGas, err := c.client.SuggestGasTipCap(context.Background())
+	maxPriorityFeePerGas, err := c.suggestTip(ctx)
 	if err != nil {
-		return 0, nil, nil, fmt.Errorf("ошибка получения предложения Gas Tip Cap: %w", err)
+		return 0, nil, nil, err
 	}
 
-	maxFeePerGas := new(big.Int).Add(header.BaseFee, maxPriorityFeePerGas)
+	maxFeePerGas, err := c.feeCaps(header.BaseFee, maxPriorityFeePerGas)
+	if err != nil {
+		return 0, nil, nil, err
+	}
 
-	gasLimit, err := c.client.EstimateGas(context.Background(), msg)
+	gasLimit, err := c.gasLimit(ctx, msg)
 	if err != nil {
-		return 0, nil, nil, fmt.Errorf("ошибка оценки газа: %w", err)
+		return 0, nil, nil, err
 	}
 
 	return gasLimit, maxPriorityFeePerGas, maxFeePerGas, nil
 }
+
+// gasLimit оценивает газ с запасом из политики. Перевод на адрес без кода стоит ровно
+// params.TxGas, поэтому для него оценка и запас не нужны.
+func (c *EthClient) gasLimit(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	if msg.To != nil && len(msg.Data) == 0 {
+		code, err := c.client.CodeAt(ctx, *msg.To, nil)
+		if err != nil {
+			return 0, fmt.Errorf("ошибка получения кода получателя: %w", err)
+		}
+		if len(code) == 0 {
+			return params.TxGas, nil
+		}
+	}
+
+	gasLimit, err := c.client.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка оценки газа: %w", err)
+	}
+
+	return c.bufferedGasLimit(gasLimit), nil
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGasLimit(t *testing.T) {
+	to := common.HexToAddress("0x1000")
+
+	tests := []struct {
+		name         string
+		code         string
+		data         []byte
+		want         uint64
+		wantEstimate bool
+	}{
+		{"transfer to EOA", "0x", nil, 21000, false},
+		{"transfer to contract", "0x6001", nil, 36000, true},
+		{"call with data", "0x", []byte{0x01}, 36000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newFakeNode(t)
+			node.handle("eth_getCode", func([]json.RawMessage) (any, error) { return tt.code, nil })
+			node.handle("eth_estimateGas", func([]json.RawMessage) (any, error) { return "0x7530", nil }) // 30000
+
+			c := &EthClient{
+				client: dialTestPool(t, PoolOptions{}, node),
+				opts:   Options{Gas: GasPolicy{GasLimitBufferPercent: 20}},
+			}
+
+			got, err := c.gasLimit(context.Background(), ethereum.CallMsg{To: &to, Data: tt.data})
+			if err != nil {
+				t.Fatalf("gasLimit: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("gasLimit = %d, want %d", got, tt.want)
+			}
+			if estimated := node.count("eth_estimateGas") > 0; estimated != tt.wantEstimate {
+				t.Errorf("eth_estimateGas called: %v, want %v", estimated, tt.wantEstimate)
+			}
+		})
+	}
+}
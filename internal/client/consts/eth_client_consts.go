@@ -16,6 +16,14 @@ func SetInit() {
 	}
 
 	Erc20ABI = &parsedABI
+
+	stakingABI, err := abi.JSON(bytes.NewReader(StakingJSON))
+	if err != nil {
+		log.Fatalf("Failed parsing staking ABI: %v", err)
+	}
+
+	StakingABI = &stakingABI
+
 	_, success := MaxApproveValue.SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
 	if !success {
 		log.Fatalf("Failed to set MaxRepayBigInt: invalid number")
@@ -30,15 +38,20 @@ var (
 	// Default ABI for erc20 tokens
 	Erc20ABI *abi.ABI
 
-	EthDecimal = 18
+	// ABI of the Monad staking precompile
+	StakingABI *abi.ABI
 
-	RetryCount = 5
+	EthDecimal = 18
 
 	ExploerTx = "https://testnet.monadexplorer.com/tx"
 
 	WaitingTimeout = 1 * time.Minute
 
-	DelegateSelector = "84994fec"
+	// Number of epochs after the undelegate activation epoch before withdraw is allowed.
+	WithdrawalDelay uint64 = 1
+
+	// Upper bound of withdrawal request slots per (validator, delegator) pair.
+	MaxWithdrawID = 255
 )
 
 // ###### Base ERC20 ABI. #######
@@ -151,5 +164,29 @@ var (
 		"stateMutability":"view",
 		"type":"function"
 	}
+]`)
+	// ###### Monad staking precompile ABI. #######
+	StakingJSON = []byte(`[
+	{"type": "function", "name": "delegate", "inputs": [{"name": "validatorId", "type": "uint64"}], "outputs": [{"name": "success", "type": "bool"}], "stateMutability": "payable"},
+	{"type": "function", "name": "undelegate", "inputs": [{"name": "validatorId", "type": "uint64"}, {"name": "amount", "type": "uint256"}, {"name": "withdrawId", "type": "uint8"}], "outputs": [{"name": "success", "type": "bool"}], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "withdraw", "inputs": [{"name": "validatorId", "type": "uint64"}, {"name": "withdrawId", "type": "uint8"}], "outputs": [{"name": "success", "type": "bool"}], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "compound", "inputs": [{"name": "validatorId", "type": "uint64"}], "outputs": [{"name": "success", "type": "bool"}], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "claimRewards", "inputs": [{"name": "validatorId", "type": "uint64"}], "outputs": [{"name": "success", "type": "bool"}], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "getEpoch", "inputs": [], "outputs": [{"name": "epoch", "type": "uint64"}, {"name": "inEpochDelayPeriod", "type": "bool"}], "stateMutability": "view"},
+	{"type": "function", "name": "getValidator", "inputs": [{"name": "validatorId", "type": "uint64"}], "outputs": [{"name": "authAddress", "type": "address"}, {"name": "flags", "type": "uint64"}, {"name": "stake", "type": "uint256"}, {"name": "accRewardPerToken", "type": "uint256"}, {"name": "commission", "type": "uint256"}, {"name": "unclaimedRewards", "type": "uint256"}, {"name": "consensusStake", "type": "uint256"}, {"name": "consensusCommission", "type": "uint256"}, {"name": "snapshotStake", "type": "uint256"}, {"name": "snapshotCommission", "type": "uint256"}, {"name": "secpPubkey", "type": "bytes"}, {"name": "blsPubkey", "type": "bytes"}], "stateMutability": "view"},
+	{"type": "function", "name": "getDelegator", "inputs": [{"name": "validatorId", "type": "uint64"}, {"name": "delegator", "type": "address"}], "outputs": [{"name": "stake", "type": "uint256"}, {"name": "accRewardPerToken", "type": "uint256"}, {"name": "unclaimedRewards", "type": "uint256"}, {"name": "deltaStake", "type": "uint256"}, {"name": "nextDeltaStake", "type": "uint256"}, {"name": "deltaEpoch", "type": "uint64"}, {"name": "nextDeltaEpoch", "type": "uint64"}], "stateMutability": "view"},
+	{"type": "function", "name": "getWithdrawalRequest", "inputs": [{"name": "validatorId", "type": "uint64"}, {"name": "delegator", "type": "address"}, {"name": "withdrawId", "type": "uint8"}], "outputs": [{"name": "withdrawalAmount", "type": "uint256"}, {"name": "accRewardPerToken", "type": "uint256"}, {"name": "withdrawEpoch", "type": "uint64"}], "stateMutability": "view"},
+	{"type": "function", "name": "getDelegations", "inputs": [{"name": "delegator", "type": "address"}, {"name": "startValId", "type": "uint64"}], "outputs": [{"name": "isDone", "type": "bool"}, {"name": "nextValId", "type": "uint64"}, {"name": "valIds", "type": "uint64[]"}], "stateMutability": "view"},
+	{"type": "function", "name": "getDelegators", "inputs": [{"name": "validatorId", "type": "uint64"}, {"name": "startDelegator", "type": "address"}], "outputs": [{"name": "isDone", "type": "bool"}, {"name": "nextDelegator", "type": "address"}, {"name": "delegators", "type": "address[]"}], "stateMutability": "view"},
+	{"type": "function", "name": "getConsensusValidatorSet", "inputs": [{"name": "startIndex", "type": "uint32"}], "outputs": [{"name": "isDone", "type": "bool"}, {"name": "nextIndex", "type": "uint32"}, {"name": "valIds", "type": "uint64[]"}], "stateMutability": "view"},
+	{"type": "function", "name": "getSnapshotValidatorSet", "inputs": [{"name": "startIndex", "type": "uint32"}], "outputs": [{"name": "isDone", "type": "bool"}, {"name": "nextIndex", "type": "uint32"}, {"name": "valIds", "type": "uint64[]"}], "stateMutability": "view"},
+	{"type": "function", "name": "getExecutionValidatorSet", "inputs": [{"name": "startIndex", "type": "uint32"}], "outputs": [{"name": "isDone", "type": "bool"}, {"name": "nextIndex", "type": "uint32"}, {"name": "valIds", "type": "uint64[]"}], "stateMutability": "view"},
+	{"type": "event", "name": "ValidatorCreated", "anonymous": false, "inputs": [{"name": "validatorId", "type": "uint64", "indexed": true}, {"name": "authAddress", "type": "address", "indexed": true}, {"name": "commission", "type": "uint256", "indexed": false}]},
+	{"type": "event", "name": "ValidatorStatusChanged", "anonymous": false, "inputs": [{"name": "validatorId", "type": "uint64", "indexed": true}, {"name": "flags", "type": "uint64", "indexed": false}]},
+	{"type": "event", "name": "Delegate", "anonymous": false, "inputs": [{"name": "validatorId", "type": "uint64", "indexed": true}, {"name": "delegator", "type": "address", "indexed": true}, {"name": "amount", "type": "uint256", "indexed": false}, {"name": "activationEpoch", "type": "uint64", "indexed": false}]},
+	{"type": "event", "name": "Undelegate", "anonymous": false, "inputs": [{"name": "validatorId", "type": "uint64", "indexed": true}, {"name": "delegator", "type": "address", "indexed": true}, {"name": "withdrawId", "type": "uint8", "indexed": false}, {"name": "amount", "type": "uint256", "indexed": false}, {"name": "activationEpoch", "type": "uint64", "indexed": false}]},
+	{"type": "event", "name": "Withdraw", "anonymous": false, "inputs": [{"name": "validatorId", "type": "uint64", "indexed": true}, {"name": "delegator", "type": "address", "indexed": true}, {"name": "withdrawId", "type": "uint8", "indexed": false}, {"name": "amount", "type": "uint256", "indexed": false}, {"name": "withdrawEpoch", "type": "uint64", "indexed": false}]},
+	{"type": "event", "name": "ClaimRewards", "anonymous": false, "inputs": [{"name": "validatorId", "type": "uint64", "indexed": true}, {"name": "delegator", "type": "address", "indexed": true}, {"name": "amount", "type": "uint256", "indexed": false}, {"name": "epoch", "type": "uint64", "indexed": false}]},
+	{"type": "event", "name": "CommissionChanged", "anonymous": false, "inputs": [{"name": "validatorId", "type": "uint64", "indexed": true}, {"name": "oldCommission", "type": "uint256", "indexed": false}, {"name": "newCommission", "type": "uint256", "indexed": false}]}
 ]`)
 )
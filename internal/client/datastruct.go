@@ -6,9 +6,75 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// Options — настройки клиента из конфигурации.
+type Options struct {
+	Pool        PoolOptions
+	Gas         GasPolicy
+	Replacement ReplacementPolicy
+}
+
 type ChainData struct {
 	Amount, ChainID, MaxPriorityFeePerGas, MaxFeePerGas *big.Int
 	GasLimit, Nonce                                     uint64
 	TxData                                              []byte
 	DestinationAddr                                     *common.Address
 }
+
+// TxPlan — подписанная, но не отправленная транзакция (результат dry-run).
+type TxPlan struct {
+	Amount               *big.Int
+	ValidatorID          uint64
+	Nonce                uint64
+	GasLimit             uint64
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	EstimatedCost        *big.Int
+	TxHash               common.Hash
+}
+
+// Delegator — позиция делегатора у конкретного валидатора (getDelegator).
+type Delegator struct {
+	Stake             *big.Int
+	AccRewardPerToken *big.Int
+	UnclaimedRewards  *big.Int
+	DeltaStake        *big.Int
+	NextDeltaStake    *big.Int
+	DeltaEpoch        uint64
+	NextDeltaEpoch    uint64
+}
+
+// WithdrawalRequest — заявка на вывод после undelegate (getWithdrawalRequest).
+type WithdrawalRequest struct {
+	WithdrawalAmount  *big.Int
+	AccRewardPerToken *big.Int
+	WithdrawEpoch     uint64
+}
+
+// Флаги состояния валидатора (поле flags в getValidator); 0 — валидатор в порядке.
+const (
+	ValidatorFlagStakeTooLow uint64 = 1 << 0
+	ValidatorFlagWithdrawn   uint64 = 1 << 1
+	ValidatorFlagDoubleSign  uint64 = 1 << 2
+)
+
+// Validator — состояние валидатора в стейкинг-контракте (getValidator).
+type Validator struct {
+	AuthAddress         common.Address
+	Flags               uint64
+	Stake               *big.Int
+	AccRewardPerToken   *big.Int
+	Commission          *big.Int
+	UnclaimedRewards    *big.Int
+	ConsensusStake      *big.Int
+	ConsensusCommission *big.Int
+	SnapshotStake       *big.Int
+	SnapshotCommission  *big.Int
+	SecpPubkey          []byte
+	BlsPubkey           []byte
+}
+
+// Epoch — текущая эпоха стейкинга (getEpoch).
+type Epoch struct {
+	Number            uint64
+	InEpochDelayPhase bool
+}
@@ -3,32 +3,28 @@ package client
 import (
 	"context"
 	"errors"
-	"fmt"
-
-	"strings"
-	"time"
-
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 type EthClient struct {
-	client *ethclient.Client
+	client *rpcPool
+	nonces *NonceManager
+	opts   Options
 }
 
-func NewEthClient(ctx context.Context, rpc string) (*EthClient, error) {
-	if strings.TrimSpace(rpc) == "" {
+// NewEthClient подключается ко всем RPC узлам; фоновые проверки узлов живут, пока не отменен ctx.
+func NewEthClient(ctx context.Context, endpoints []Endpoint, opts Options) (*EthClient, error) {
+	if len(endpoints) == 0 {
 		return nil, errors.New("RPC is nil")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	client, err := ethclient.DialContext(ctx, rpc)
+	pool, err := dialPool(ctx, endpoints, opts.Pool)
 	if err != nil {
-		return nil, fmt.Errorf("error connecting to RPC %s: %v", rpc, err)
+		return nil, err
 	}
 
 	return &EthClient{
-		client: client,
+		client: pool,
+		nonces: NewNonceManager(pool.PendingNonceAt),
+		opts:   opts,
 	}, nil
 }
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// nodeError — ответ JSON-RPC с ошибкой; code 0 превращается в -32000 (ошибка узла).
+type nodeError struct {
+	code    int
+	message string
+}
+
+func (e nodeError) Error() string { return e.message }
+
+// httpStatus — ответ узла без JSON-RPC тела, например 503.
+type httpStatus int
+
+func (s httpStatus) Error() string { return http.StatusText(int(s)) }
+
+// fakeNode — минимальный JSON-RPC узел для тестов пула и клиента.
+type fakeNode struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]func(params []json.RawMessage) (any, error)
+	calls    map[string]int
+}
+
+func newFakeNode(t *testing.T) *fakeNode {
+	t.Helper()
+
+	n := &fakeNode{
+		t:        t,
+		handlers: make(map[string]func([]json.RawMessage) (any, error)),
+		calls:    make(map[string]int),
+	}
+	n.srv = httptest.NewServer(http.HandlerFunc(n.serve))
+	t.Cleanup(n.srv.Close)
+
+	// Проверка здоровья пула при подключении
+	n.handle("eth_blockNumber", func([]json.RawMessage) (any, error) { return "0x1", nil })
+
+	return n
+}
+
+func (n *fakeNode) URL() string {
+	return n.srv.URL
+}
+
+func (n *fakeNode) handle(method string, fn func(params []json.RawMessage) (any, error)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.handlers[method] = fn
+}
+
+func (n *fakeNode) count(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.calls[method]
+}
+
+func (n *fakeNode) serve(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mu.Lock()
+	n.calls[req.Method]++
+	fn, ok := n.handlers[req.Method]
+	n.mu.Unlock()
+
+	resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+	if !ok {
+		resp["error"] = map[string]any{"code": -32601, "message": "method " + req.Method + " not found"}
+	} else {
+		res, err := fn(req.Params)
+		switch e := err.(type) {
+		case nil:
+			resp["result"] = res
+		case httpStatus:
+			http.Error(w, e.Error(), int(e))
+			return
+		case nodeError:
+			code := e.code
+			if code == 0 {
+				code = -32000
+			}
+			resp["error"] = map[string]any{"code": code, "message": e.message}
+		default:
+			resp["error"] = map[string]any{"code": -32000, "message": err.Error()}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// dialTestPool подключает пул к узлам без фоновых проверок и повторов с задержкой.
+func dialTestPool(t *testing.T, opts PoolOptions, nodes ...*fakeNode) *rpcPool {
+	t.Helper()
+
+	endpoints := make([]Endpoint, len(nodes))
+	for i, n := range nodes {
+		endpoints[i] = Endpoint{URL: n.URL(), Priority: i}
+	}
+
+	if opts.Retry.BaseDelay == 0 {
+		opts.Retry.BaseDelay = time.Millisecond
+		opts.Retry.MaxDelay = time.Millisecond
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	pool, err := dialPool(ctx, endpoints, opts)
+	if err != nil {
+		t.Fatalf("dialPool: %v", err)
+	}
+
+	return pool
+}
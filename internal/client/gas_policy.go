@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+var ErrFeeAboveCap = errors.New("network fee above configured cap")
+
+// GasPolicy — правила расчета комиссий и лимита газа.
+type GasPolicy struct {
+	// maxFeePerGas = baseFee * BaseFeeMultiplier + tip; 0 — множитель 1.
+	BaseFeeMultiplier float64
+	// Фиксированный tip в wei; nil — tip берется из сети.
+	TipOverride *big.Int
+	// Перцентиль наград eth_feeHistory для tip; 0 — eth_maxPriorityFeePerGas.
+	TipPercentile float64
+	// Сколько последних блоков брать в eth_feeHistory.
+	FeeHistoryBlocks uint64
+	// Запас к результату eth_estimateGas, проценты.
+	GasLimitBufferPercent uint64
+	// Абсолютный потолок maxFeePerGas в wei; если baseFee + tip выше, отправка откладывается. nil — без потолка.
+	MaxFeePerGas *big.Int
+	// Пауза между проверками комиссии, пока она выше потолка.
+	WaitInterval time.Duration
+}
+
+func (c *EthClient) suggestTip(ctx context.Context) (*big.Int, error) {
+	policy := c.opts.Gas
+
+	if policy.TipOverride != nil {
+		return new(big.Int).Set(policy.TipOverride), nil
+	}
+
+	if policy.TipPercentile <= 0 {
+		tip, err := c.client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения предложения Gas Tip Cap: %w", err)
+		}
+		return tip, nil
+	}
+
+	history, err := c.client.FeeHistory(ctx, policy.FeeHistoryBlocks, nil, []float64{policy.TipPercentile})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения eth_feeHistory: %w", err)
+	}
+
+	var rewards []*big.Int
+	for _, blockRewards := range history.Reward {
+		if len(blockRewards) > 0 && blockRewards[0] != nil {
+			rewards = append(rewards, blockRewards[0])
+		}
+	}
+
+	if len(rewards) == 0 {
+		return nil, fmt.Errorf("eth_feeHistory returned no rewards")
+	}
+
+	// Медиана по блокам сглаживает одиночные всплески
+	sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+	return new(big.Int).Set(rewards[len(rewards)/2]), nil
+}
+
+// feeCaps считает maxFeePerGas по политике и проверяет потолок.
+func (c *EthClient) feeCaps(baseFee, tip *big.Int) (*big.Int, error) {
+	policy := c.opts.Gas
+
+	if policy.MaxFeePerGas != nil {
+		if required := new(big.Int).Add(baseFee, tip); required.Cmp(policy.MaxFeePerGas) > 0 {
+			return nil, fmt.Errorf("%w: base fee %s + tip %s wei > %s wei", ErrFeeAboveCap, baseFee, tip, policy.MaxFeePerGas)
+		}
+	}
+
+	multiplier := policy.BaseFeeMultiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+
+	scaledBaseFee, _ := new(big.Float).Mul(new(big.Float).SetInt(baseFee), big.NewFloat(multiplier)).Int(nil)
+	maxFeePerGas := scaledBaseFee.Add(scaledBaseFee, tip)
+
+	if policy.MaxFeePerGas != nil && maxFeePerGas.Cmp(policy.MaxFeePerGas) > 0 {
+		maxFeePerGas = new(big.Int).Set(policy.MaxFeePerGas)
+	}
+
+	return maxFeePerGas, nil
+}
+
+func (c *EthClient) bufferedGasLimit(estimate uint64) uint64 {
+	return estimate + estimate*c.opts.Gas.GasLimitBufferPercent/100
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceManager выдает nonce локально для каждого адреса. Стартовое значение берется из сети
+// (pending nonce); пока у адреса нет транзакций в полете, значение сверяется с сетью на каждой
+// выдаче, чтобы заметить транзакции, отправленные в обход бота. Транзакция, которую не дождались
+// (Expire), тоже вызывает сверку: если она выпала из mempool, сеть вернет ее nonce и пропуск заполнится.
+type NonceManager struct {
+	mu     sync.Mutex
+	fetch  func(ctx context.Context, addr common.Address) (uint64, error)
+	states map[common.Address]*nonceState
+}
+
+type nonceState struct {
+	next     uint64
+	synced   bool
+	inflight map[uint64]struct{}
+}
+
+func NewNonceManager(fetch func(ctx context.Context, addr common.Address) (uint64, error)) *NonceManager {
+	return &NonceManager{
+		fetch:  fetch,
+		states: make(map[common.Address]*nonceState),
+	}
+}
+
+// Next резервирует следующий nonce адреса. Зарезервированный nonce нужно вернуть через
+// Release, если транзакция не ушла в сеть, или закрыть через Confirm после включения в блок.
+func (m *NonceManager) Next(ctx context.Context, addr common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st := m.state(addr)
+	if !st.synced || len(st.inflight) == 0 {
+		chainNonce, err := m.fetch(ctx, addr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get nonce for %s: %w", addr.Hex(), err)
+		}
+
+		if st.synced && chainNonce != st.next {
+			log.Printf("[WARN] nonce of %s out of sync (local %d, chain %d), resyncing", addr.Hex()[:10], st.next, chainNonce)
+		}
+
+		st.next = chainNonce
+		st.synced = true
+	}
+
+	// После заполнения пропуска следующие nonce могут быть еще заняты транзакциями в полете
+	nonce := st.next
+	for {
+		if _, busy := st.inflight[nonce]; !busy {
+			break
+		}
+		nonce++
+	}
+	st.next = nonce + 1
+	st.inflight[nonce] = struct{}{}
+
+	return nonce, nil
+}
+
+// Confirm отмечает, что транзакция с nonce включена в блок (успешно или с revert).
+func (m *NonceManager) Confirm(addr common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.state(addr).inflight, nonce)
+}
+
+// Release возвращает nonce транзакции, которая так и не была отправлена. Если это не последний
+// выданный nonce, в последовательности остается дыра, и адрес пересинхронизируется с сетью.
+func (m *NonceManager) Release(addr common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st := m.state(addr)
+	delete(st.inflight, nonce)
+
+	if nonce+1 == st.next {
+		st.next = nonce
+		return
+	}
+
+	st.synced = false
+}
+
+// Expire отмечает транзакцию, которую не дождались: она могла выпасть из mempool, оставив пропуск.
+// Следующий Next сверится с pending nonce сети: если транзакция еще в mempool, сеть ее учтет,
+// если выпала — ее nonce будет выдан заново.
+func (m *NonceManager) Expire(addr common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st := m.state(addr)
+	delete(st.inflight, nonce)
+	st.synced = false
+}
+
+// Resync сбрасывает локальное состояние адреса: следующий Next возьмет nonce из сети.
+func (m *NonceManager) Resync(addr common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st := m.state(addr)
+	st.synced = false
+	st.inflight = make(map[uint64]struct{})
+}
+
+func (m *NonceManager) state(addr common.Address) *nonceState {
+	st, ok := m.states[addr]
+	if !ok {
+		st = &nonceState{inflight: make(map[uint64]struct{})}
+		m.states[addr] = st
+	}
+
+	return st
+}
+
+func isNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
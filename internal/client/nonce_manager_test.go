@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeChain — pending nonce сети для NonceManager.
+type fakeChain struct {
+	nonce   uint64
+	err     error
+	fetches int
+}
+
+func (f *fakeChain) fetch(context.Context, common.Address) (uint64, error) {
+	f.fetches++
+	return f.nonce, f.err
+}
+
+// nonceOp — шаг сценария: op с аргументом nonce; для next — ожидаемый nonce.
+type nonceOp struct {
+	op    string // next, confirm, release, expire, resync, chain
+	nonce uint64
+}
+
+func TestNonceManager(t *testing.T) {
+	tests := []struct {
+		name        string
+		chain       uint64
+		ops         []nonceOp
+		wantFetches int
+	}{
+		{
+			name:        "sequential without refetch while in flight",
+			chain:       5,
+			ops:         []nonceOp{{"next", 5}, {"next", 6}, {"next", 7}},
+			wantFetches: 1,
+		},
+		{
+			name:  "refetch when nothing in flight",
+			chain: 5,
+			ops: []nonceOp{
+				{"next", 5}, {"confirm", 5},
+				{"chain", 9}, // транзакции, отправленные в обход бота
+				{"next", 9},
+			},
+			wantFetches: 2,
+		},
+		{
+			name:  "release of the last nonce reuses it",
+			chain: 5,
+			ops:   []nonceOp{{"next", 5}, {"next", 6}, {"release", 6}, {"next", 6}},
+			// Транзакция 5 в полете — сверка не нужна
+			wantFetches: 1,
+		},
+		{
+			name:  "release in the middle resyncs",
+			chain: 5,
+			ops: []nonceOp{
+				{"next", 5}, {"next", 6}, {"next", 7},
+				{"release", 6},
+				{"chain", 6}, // сеть видит только 5
+				{"next", 6},  // пропуск заполнен
+				{"next", 8},  // 7 еще в полете
+			},
+			wantFetches: 2,
+		},
+		{
+			name:  "expired tx dropped from mempool fills the gap",
+			chain: 5,
+			ops: []nonceOp{
+				{"next", 5}, {"next", 6}, {"next", 7},
+				{"expire", 5},
+				{"next", 5}, // сеть вернула nonce выпавшей транзакции
+				{"next", 8}, // 6 и 7 еще в полете
+			},
+			wantFetches: 2,
+		},
+		{
+			name:  "expired tx still in mempool is not reused",
+			chain: 5,
+			ops: []nonceOp{
+				{"next", 5}, {"next", 6},
+				{"expire", 5},
+				{"chain", 7}, // обе транзакции в mempool
+				{"next", 7},
+			},
+			wantFetches: 2,
+		},
+		{
+			name:  "resync drops in-flight state",
+			chain: 5,
+			ops: []nonceOp{
+				{"next", 5}, {"next", 6},
+				{"resync", 0},
+				{"chain", 5},
+				{"next", 5},
+				{"next", 6},
+			},
+			wantFetches: 2,
+		},
+	}
+
+	addr := common.HexToAddress("0x1")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := &fakeChain{nonce: tt.chain}
+			m := NewNonceManager(chain.fetch)
+
+			for i, op := range tt.ops {
+				switch op.op {
+				case "next":
+					got, err := m.Next(context.Background(), addr)
+					if err != nil {
+						t.Fatalf("step %d: Next: %v", i, err)
+					}
+					if got != op.nonce {
+						t.Fatalf("step %d: Next = %d, want %d", i, got, op.nonce)
+					}
+				case "confirm":
+					m.Confirm(addr, op.nonce)
+				case "release":
+					m.Release(addr, op.nonce)
+				case "expire":
+					m.Expire(addr, op.nonce)
+				case "resync":
+					m.Resync(addr)
+				case "chain":
+					chain.nonce = op.nonce
+				default:
+					t.Fatalf("unknown op %q", op.op)
+				}
+			}
+
+			if chain.fetches != tt.wantFetches {
+				t.Errorf("fetches = %d, want %d", chain.fetches, tt.wantFetches)
+			}
+		})
+	}
+}
+
+func TestNonceManagerFetchError(t *testing.T) {
+	errRPC := errors.New("rpc down")
+	chain := &fakeChain{nonce: 5, err: errRPC}
+	m := NewNonceManager(chain.fetch)
+	addr := common.HexToAddress("0x1")
+
+	if _, err := m.Next(context.Background(), addr); !errors.Is(err, errRPC) {
+		t.Fatalf("Next error = %v, want %v", err, errRPC)
+	}
+
+	// После ошибки адрес остается несинхронизированным и следующая выдача снова идет в сеть
+	chain.err = nil
+	if got, err := m.Next(context.Background(), addr); err != nil || got != 5 {
+		t.Fatalf("Next = %d, %v; want 5", got, err)
+	}
+}
+
+func TestNonceManagerAddressesAreIndependent(t *testing.T) {
+	chain := &fakeChain{nonce: 3}
+	m := NewNonceManager(chain.fetch)
+	a, b := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+
+	for _, want := range []struct {
+		addr  common.Address
+		nonce uint64
+	}{{a, 3}, {b, 3}, {a, 4}, {b, 4}} {
+		got, err := m.Next(context.Background(), want.addr)
+		if err != nil || got != want.nonce {
+			t.Fatalf("Next(%s) = %d, %v; want %d", want.addr.Hex(), got, err, want.nonce)
+		}
+	}
+}
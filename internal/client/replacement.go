@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	client "ms/internal/client/consts"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Минимальный прирост комиссий, с которым узлы принимают замену транзакции с тем же nonce.
+const minReplacementBumpPercent = 10
+
+// Интервал проверки receipt при ожидании транзакции.
+var receiptPollInterval = 3 * time.Second
+
+// ReplacementPolicy — правила замены зависших транзакций.
+type ReplacementPolicy struct {
+	// Через сколько блоков без включения транзакция считается зависшей; 0 — замена выключена.
+	StuckBlocks uint64
+	// На сколько процентов поднимаются GasTipCap и GasFeeCap при каждой замене.
+	BumpPercent uint64
+	// Потолок GasFeeCap в wei; nil — без ограничения.
+	MaxFeePerGas *big.Int
+}
+
+// waitMined ждет, пока в блок попадет signedTx или одна из его замен. Если транзакция не
+// включена за policy.StuckBlocks блоков, она переподписывается с тем же nonce и поднятыми
+// комиссиями (до потолка). onReplace вызывается для каждой отправленной замены.
+//
+// С включенной заменой ожидание длится до отмены ctx: вернуть ErrTxTimeout значило бы бросить
+// транзакцию, которая занимает nonce. Без замены ожидание ограничено client.WaitingTimeout.
+func (c *EthClient) waitMined(ctx context.Context, signedTx *types.Transaction, signer Signer, onReplace func(*types.Transaction)) (*types.Transaction, *types.Receipt, error) {
+	policy := c.opts.Replacement
+	sent := []*types.Transaction{signedTx}
+	current := signedTx
+
+	replaceEnabled := policy.StuckBlocks > 0 && signer != nil
+	canReplace := replaceEnabled
+
+	// Высота блока отправки запоминается при первом успешном чтении: без нее транзакция
+	// заменялась бы на первой же проверке, а ошибка чтения не должна прерывать ожидание
+	var (
+		sentAtBlock uint64
+		haveBlock   bool
+	)
+	if replaceEnabled {
+		if block, err := c.client.BlockNumber(ctx); err == nil {
+			sentAtBlock, haveBlock = block, true
+		} else {
+			log.Printf("error getting block number: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(client.WaitingTimeout)
+
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Транзакция уже в сети и может попасть в блок — nonce остается занятым
+			return current, nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		// Проверяем с самой свежей замены: в блок могла попасть любая из отправленных версий
+		for i := len(sent) - 1; i >= 0; i-- {
+			receipt, err := c.client.TransactionReceipt(ctx, sent[i].Hash())
+			if errors.Is(err, ethereum.NotFound) {
+				continue
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			if err != nil {
+				log.Printf("error getting transaction receipt: %v", err)
+				continue
+			}
+
+			if receipt.Status == types.ReceiptStatusSuccessful {
+				return sent[i], receipt, nil
+			}
+			return sent[i], receipt, ErrTxReverted
+		}
+
+		if err := ctx.Err(); err != nil {
+			return current, nil, err
+		}
+
+		if !replaceEnabled && time.Now().After(deadline) {
+			return current, nil, ErrTxTimeout
+		}
+
+		if !canReplace {
+			continue
+		}
+
+		block, err := c.client.BlockNumber(ctx)
+		if err != nil {
+			log.Printf("error getting block number: %v", err)
+			continue
+		}
+
+		if !haveBlock {
+			sentAtBlock, haveBlock = block, true
+			continue
+		}
+
+		if block < sentAtBlock+policy.StuckBlocks {
+			continue
+		}
+
+		replacement, err := c.replaceTx(ctx, current, signer)
+		if err != nil {
+			log.Printf("[WARN] tx %s stuck for %d blocks, no replacement: %v", current.Hash().Hex(), block-sentAtBlock, err)
+			canReplace = false
+			continue
+		}
+
+		if err := c.client.SendTransaction(ctx, replacement); err != nil {
+			log.Printf("[WARN] failed to send replacement of %s: %v", current.Hash().Hex(), err)
+			sentAtBlock = block
+			continue
+		}
+
+		log.Printf("[NONCE: %v] Tx %s stuck for %d blocks, replaced: %s/%s (tip %s, fee cap %s wei)",
+			replacement.Nonce(), current.Hash().Hex(), block-sentAtBlock, client.ExploerTx, replacement.Hash().Hex(),
+			replacement.GasTipCap(), replacement.GasFeeCap())
+
+		sent = append(sent, replacement)
+		current = replacement
+		sentAtBlock = block
+
+		if onReplace != nil {
+			onReplace(replacement)
+		}
+	}
+}
+
+// replaceTx переподписывает транзакцию с тем же nonce и комиссиями, поднятыми на BumpPercent.
+func (c *EthClient) replaceTx(ctx context.Context, tx *types.Transaction, signer Signer) (*types.Transaction, error) {
+	policy := c.opts.Replacement
+
+	bump := policy.BumpPercent
+	if bump < minReplacementBumpPercent {
+		bump = minReplacementBumpPercent
+	}
+
+	tipCap := bumpByPercent(tx.GasTipCap(), bump)
+	feeCap := bumpByPercent(tx.GasFeeCap(), bump)
+
+	if policy.MaxFeePerGas != nil && feeCap.Cmp(policy.MaxFeePerGas) > 0 {
+		feeCap = new(big.Int).Set(policy.MaxFeePerGas)
+		if feeCap.Cmp(bumpByPercent(tx.GasFeeCap(), minReplacementBumpPercent)) < 0 {
+			return nil, fmt.Errorf("fee ceiling %s wei reached", policy.MaxFeePerGas)
+		}
+	}
+
+	if tipCap.Cmp(feeCap) > 0 {
+		tipCap = new(big.Int).Set(feeCap)
+	}
+
+	replacement := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   tx.ChainId(),
+		Nonce:     tx.Nonce(),
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       tx.Gas(),
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+	})
+
+	signedTx, err := signer.SignTx(ctx, replacement, tx.ChainId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign replacement: %v", err)
+	}
+
+	return signedTx, nil
+}
+
+// replacementFeeCap — GasFeeCap, на который нужно рассчитывать баланс, чтобы замена зависшей
+// транзакции не упала в insufficient funds: потолок замен, а без потолка — одна замена.
+// Без потолка вторая и следующие замены могут не пройти, если баланс ушел в стейк целиком.
+func (c *EthClient) replacementFeeCap(feeCap *big.Int) *big.Int {
+	policy := c.opts.Replacement
+	if policy.StuckBlocks == 0 {
+		return feeCap
+	}
+
+	if policy.MaxFeePerGas != nil {
+		if policy.MaxFeePerGas.Cmp(feeCap) > 0 {
+			return new(big.Int).Set(policy.MaxFeePerGas)
+		}
+		return feeCap
+	}
+
+	return bumpByPercent(feeCap, max(policy.BumpPercent, minReplacementBumpPercent))
+}
+
+func bumpByPercent(value *big.Int, percent uint64) *big.Int {
+	bumped := new(big.Int).Mul(value, new(big.Int).SetUint64(100+percent))
+	bumped.Div(bumped, big.NewInt(100))
+
+	// Целочисленное деление для маленьких значений может не дать прироста
+	if bumped.Cmp(value) <= 0 {
+		bumped.Add(value, big.NewInt(1))
+	}
+
+	return bumped
+}
@@ -0,0 +1,303 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testChainID = big.NewInt(10143)
+
+func newTestSigner(t *testing.T) *KeySigner {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewKeySigner(key)
+}
+
+func signTestTx(t *testing.T, signer Signer, nonce uint64, tip, feeCap int64) *types.Transaction {
+	t.Helper()
+
+	to := common.HexToAddress("0x0000000000000000000000000000000000001000")
+	tx, err := signer.SignTx(context.Background(), types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(feeCap),
+		Gas:       100000,
+		To:        &to,
+		Value:     big.NewInt(7),
+		Data:      []byte{0xde, 0xad},
+	}), testChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tx
+}
+
+func TestBumpByPercent(t *testing.T) {
+	tests := []struct {
+		value   int64
+		percent uint64
+		want    int64
+	}{
+		{100, 10, 110},
+		{1000, 15, 1150},
+		{1000000007, 10, 1100000007},
+		// Целочисленное деление не дает прироста — прибавляется 1
+		{5, 10, 6},
+		{0, 10, 1},
+		{100, 0, 101},
+	}
+
+	for _, tt := range tests {
+		if got := bumpByPercent(big.NewInt(tt.value), tt.percent); got.Int64() != tt.want {
+			t.Errorf("bumpByPercent(%d, %d) = %s, want %d", tt.value, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestReplaceTx(t *testing.T) {
+	tests := []struct {
+		name        string
+		bump        uint64
+		ceiling     int64 // 0 — без потолка
+		tip, feeCap int64
+		wantTip     int64
+		wantFeeCap  int64
+		wantErr     bool
+	}{
+		{"configured bump", 20, 0, 100, 1000, 120, 1200, false},
+		{"bump below minimum uses 10%", 5, 0, 100, 1000, 110, 1100, false},
+		{"ceiling clamps fee cap", 50, 1200, 100, 1000, 150, 1200, false},
+		{"ceiling exactly at minimum bump", 50, 1100, 100, 1000, 150, 1100, false},
+		{"ceiling below minimum bump", 50, 1099, 100, 1000, 0, 0, true},
+		{"tip clamped to fee cap", 50, 1200, 1000, 1000, 1200, 1200, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &EthClient{opts: Options{Replacement: ReplacementPolicy{StuckBlocks: 1, BumpPercent: tt.bump}}}
+			if tt.ceiling > 0 {
+				c.opts.Replacement.MaxFeePerGas = big.NewInt(tt.ceiling)
+			}
+
+			signer := newTestSigner(t)
+			tx := signTestTx(t, signer, 4, tt.tip, tt.feeCap)
+
+			replacement, err := c.replaceTx(context.Background(), tx, signer)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("replaceTx = fee cap %s, want error", replacement.GasFeeCap())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("replaceTx: %v", err)
+			}
+
+			if replacement.GasTipCap().Int64() != tt.wantTip || replacement.GasFeeCap().Int64() != tt.wantFeeCap {
+				t.Errorf("tip %s, fee cap %s; want %d, %d", replacement.GasTipCap(), replacement.GasFeeCap(), tt.wantTip, tt.wantFeeCap)
+			}
+
+			// Меняются только комиссии
+			if replacement.Nonce() != tx.Nonce() || replacement.Gas() != tx.Gas() || *replacement.To() != *tx.To() ||
+				replacement.Value().Cmp(tx.Value()) != 0 || string(replacement.Data()) != string(tx.Data()) {
+				t.Errorf("replacement changed more than fees: %+v", replacement)
+			}
+			if from, err := txSender(replacement); err != nil || from != signer.Address() {
+				t.Errorf("replacement sender %s, %v; want %s", from.Hex(), err, signer.Address().Hex())
+			}
+		})
+	}
+}
+
+// stuckTxNode — узел, на котором транзакция висит, пока не будет отправлена замена; затем
+// receipt есть у версий из mined.
+type stuckTxNode struct {
+	*fakeNode
+
+	mu       sync.Mutex
+	block    uint64
+	replaced []common.Hash
+	mined    func(original common.Hash, replaced []common.Hash) []common.Hash
+}
+
+func newStuckTxNode(t *testing.T, original common.Hash, mined func(original common.Hash, replaced []common.Hash) []common.Hash) *stuckTxNode {
+	n := &stuckTxNode{fakeNode: newFakeNode(t), mined: mined}
+
+	n.handle("eth_blockNumber", func([]json.RawMessage) (any, error) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		n.block++
+		return hexutil.Uint64(n.block), nil
+	})
+
+	n.handle("eth_sendRawTransaction", func(params []json.RawMessage) (any, error) {
+		var raw hexutil.Bytes
+		if err := json.Unmarshal(params[0], &raw); err != nil {
+			return nil, err
+		}
+
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return nil, err
+		}
+
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.replaced = append(n.replaced, tx.Hash())
+
+		return tx.Hash(), nil
+	})
+
+	n.handle("eth_getTransactionReceipt", func(params []json.RawMessage) (any, error) {
+		var hash common.Hash
+		if err := json.Unmarshal(params[0], &hash); err != nil {
+			return nil, err
+		}
+
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		if len(n.replaced) == 0 {
+			return nil, nil
+		}
+		for _, h := range n.mined(original, n.replaced) {
+			if h == hash {
+				return &types.Receipt{
+					Status:            types.ReceiptStatusSuccessful,
+					CumulativeGasUsed: 21000,
+					GasUsed:           21000,
+					Logs:              []*types.Log{},
+					TxHash:            hash,
+					BlockNumber:       big.NewInt(int64(n.block)),
+				}, nil
+			}
+		}
+
+		return nil, nil
+	})
+
+	return n
+}
+
+func TestWaitMinedReceiptOrder(t *testing.T) {
+	defer func(interval time.Duration) { receiptPollInterval = interval }(receiptPollInterval)
+	receiptPollInterval = 5 * time.Millisecond
+
+	tests := []struct {
+		name         string
+		mined        func(original common.Hash, replaced []common.Hash) []common.Hash
+		wantOriginal bool
+	}{
+		{
+			name: "newest version is checked first",
+			mined: func(original common.Hash, replaced []common.Hash) []common.Hash {
+				return append([]common.Hash{original}, replaced...)
+			},
+		},
+		{
+			name: "original mined after replacement was sent",
+			mined: func(original common.Hash, _ []common.Hash) []common.Hash {
+				return []common.Hash{original}
+			},
+			wantOriginal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := newTestSigner(t)
+			tx := signTestTx(t, signer, 0, 100, 1000)
+
+			node := newStuckTxNode(t, tx.Hash(), tt.mined)
+			c := &EthClient{
+				client: dialTestPool(t, PoolOptions{}, node.fakeNode),
+				opts:   Options{Replacement: ReplacementPolicy{StuckBlocks: 2, BumpPercent: 10}},
+			}
+
+			var replacements []*types.Transaction
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			minedTx, receipt, err := c.waitMined(ctx, tx, signer, func(r *types.Transaction) {
+				replacements = append(replacements, r)
+			})
+			if err != nil {
+				t.Fatalf("waitMined: %v", err)
+			}
+
+			if len(replacements) != 1 {
+				t.Fatalf("%d replacements, want 1", len(replacements))
+			}
+
+			want := replacements[0]
+			if tt.wantOriginal {
+				want = tx
+			}
+			if minedTx.Hash() != want.Hash() || receipt.TxHash != want.Hash() {
+				t.Errorf("mined %s (receipt %s), want %s", minedTx.Hash().Hex(), receipt.TxHash.Hex(), want.Hash().Hex())
+			}
+		})
+	}
+}
+
+func TestWaitMinedWithoutReplacementTimesOut(t *testing.T) {
+	defer func(interval time.Duration) { receiptPollInterval = interval }(receiptPollInterval)
+	receiptPollInterval = 5 * time.Millisecond
+
+	signer := newTestSigner(t)
+	tx := signTestTx(t, signer, 0, 100, 1000)
+
+	node := newFakeNode(t)
+	node.handle("eth_getTransactionReceipt", func([]json.RawMessage) (any, error) { return nil, nil })
+	c := &EthClient{client: dialTestPool(t, PoolOptions{}, node), opts: Options{Replacement: ReplacementPolicy{StuckBlocks: 2}}}
+
+	// Без signer замена выключена: ожидание ограничено таймаутом, здесь — отменой ctx
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := c.waitMined(ctx, tx, nil, nil); err == nil {
+		t.Fatal("waitMined returned without a receipt")
+	}
+	if n := node.count("eth_sendRawTransaction"); n != 0 {
+		t.Errorf("%d replacements sent without a signer", n)
+	}
+}
+
+func TestReplacementFeeCap(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ReplacementPolicy
+		feeCap int64
+		want   int64
+	}{
+		{"replacement disabled", ReplacementPolicy{BumpPercent: 50}, 1000, 1000},
+		{"one bump without ceiling", ReplacementPolicy{StuckBlocks: 5, BumpPercent: 20}, 1000, 1200},
+		{"bump below minimum", ReplacementPolicy{StuckBlocks: 5, BumpPercent: 5}, 1000, 1100},
+		{"ceiling", ReplacementPolicy{StuckBlocks: 5, BumpPercent: 20, MaxFeePerGas: big.NewInt(5000)}, 1000, 5000},
+		{"ceiling below fee cap", ReplacementPolicy{StuckBlocks: 5, BumpPercent: 20, MaxFeePerGas: big.NewInt(500)}, 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		c := &EthClient{opts: Options{Replacement: tt.policy}}
+		if got := c.replacementFeeCap(big.NewInt(tt.feeCap)); got.Int64() != tt.want {
+			t.Errorf("%s: replacementFeeCap(%d) = %s, want %d", tt.name, tt.feeCap, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RetryPolicy — повтор RPC вызовов при временных сбоях узлов.
+type RetryPolicy struct {
+	// Сколько раз обойти все узлы пула, прежде чем вернуть ошибку; <= 1 — без повторов.
+	MaxAttempts int
+	// Базовая задержка экспоненциального backoff.
+	BaseDelay time.Duration
+	// Максимальная задержка между попытками.
+	MaxDelay time.Duration
+}
+
+type errClass int
+
+const (
+	// Ответ сети (revert, nonce too low, insufficient funds, not found) — повторять бессмысленно.
+	classNode errClass = iota
+	// Постоянный сбой узла (например, 401/403) — переключаемся на другой узел без повторов.
+	classEndpoint
+	// Временный сбой (лимит запросов, таймаут, обрыв соединения, 5xx) — переключаемся и повторяем с backoff.
+	classTransient
+)
+
+var transientMessages = []string{
+	"rate limit",
+	"too many requests",
+	"limit exceeded",
+	"capacity exceeded",
+	"timeout",
+	"timed out",
+	"connection reset",
+	"connection refused",
+	"broken pipe",
+	"eof",
+	"service unavailable",
+	"bad gateway",
+}
+
+func classifyError(err error) errClass {
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) {
+		return classNode
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode == http.StatusRequestTimeout || httpErr.StatusCode >= 500 {
+			return classTransient
+		}
+		return classEndpoint
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		// -32005: limit exceeded у большинства провайдеров, -32002: request timed out
+		if rpcErr.ErrorCode() == -32005 || rpcErr.ErrorCode() == -32002 || hasTransientMessage(err) {
+			return classTransient
+		}
+		return classNode
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.As(err, &netErr) {
+		return classTransient
+	}
+
+	if hasTransientMessage(err) {
+		return classTransient
+	}
+
+	// Прочие ошибки без кода JSON-RPC — сбой транспорта или узла
+	return classEndpoint
+}
+
+func hasTransientMessage(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, m := range transientMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isEndpointError отделяет сбои самого узла (сеть, HTTP, лимиты) от ответов сети
+// (revert, nonce too low, not found), при которых переключение узла бессмысленно.
+func isEndpointError(err error) bool {
+	return err != nil && classifyError(err) != classNode
+}
+
+// backoff возвращает задержку перед попыткой attempt (с 1): экспонента с полным jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int64N(int64(delay)) + 1)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
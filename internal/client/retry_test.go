@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// codeError — ошибка JSON-RPC с кодом, как ее возвращает go-ethereum.
+type codeError struct {
+	code    int
+	message string
+}
+
+func (e codeError) Error() string  { return e.message }
+func (e codeError) ErrorCode() int { return e.code }
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errClass
+	}{
+		{"not found", ethereum.NotFound, classNode},
+		{"wrapped not found", fmt.Errorf("receipt: %w", ethereum.NotFound), classNode},
+		{"canceled", context.Canceled, classNode},
+		{"revert", codeError{3, "execution reverted"}, classNode},
+		{"nonce too low", codeError{-32000, "nonce too low"}, classNode},
+		{"insufficient funds", codeError{-32000, "insufficient funds for gas * price + value"}, classNode},
+		{"limit exceeded code", codeError{-32005, "request limit reached"}, classTransient},
+		{"request timed out code", codeError{-32002, "request timed out"}, classTransient},
+		{"rate limit message", codeError{-32000, "Rate limit exceeded"}, classTransient},
+		{"http 429", rpc.HTTPError{StatusCode: http.StatusTooManyRequests}, classTransient},
+		{"http 408", rpc.HTTPError{StatusCode: http.StatusRequestTimeout}, classTransient},
+		{"http 502", rpc.HTTPError{StatusCode: http.StatusBadGateway}, classTransient},
+		{"http 503", rpc.HTTPError{StatusCode: http.StatusServiceUnavailable}, classTransient},
+		{"http 401", rpc.HTTPError{StatusCode: http.StatusUnauthorized}, classEndpoint},
+		{"http 403", rpc.HTTPError{StatusCode: http.StatusForbidden}, classEndpoint},
+		{"deadline", context.DeadlineExceeded, classTransient},
+		{"eof", io.EOF, classTransient},
+		{"unexpected eof", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), classTransient},
+		{"connection reset", &net.OpError{Op: "read", Err: syscall.ECONNRESET}, classTransient},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), classTransient},
+		{"transient text", errors.New("upstream timed out"), classTransient},
+		{"unknown transport error", errors.New("invalid character '<' looking for beginning of value"), classEndpoint},
+	}
+
+	for _, tt := range tests {
+		if got := classifyError(tt.err); got != tt.want {
+			t.Errorf("%s: classifyError(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+
+	if isEndpointError(nil) {
+		t.Error("isEndpointError(nil) = true")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		max     time.Duration
+	}{
+		{"first attempt", RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 1, 100 * time.Millisecond},
+		{"exponent", RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 3, 400 * time.Millisecond},
+		{"capped by max delay", RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 6, time.Second},
+		{"shift overflow capped", RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, 70, 5 * time.Second},
+		{"no max delay", RetryPolicy{BaseDelay: 10 * time.Millisecond}, 2, 20 * time.Millisecond},
+		{"no delay", RetryPolicy{}, 3, 0},
+	}
+
+	for _, tt := range tests {
+		for range 100 {
+			got := tt.policy.backoff(tt.attempt)
+			if got < 0 || got > tt.max || (tt.max > 0 && got == 0) {
+				t.Fatalf("%s: backoff(%d) = %s, want (0, %s]", tt.name, tt.attempt, got, tt.max)
+			}
+		}
+	}
+}
+
+func TestPoolCallRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int
+	}{
+		{"transient error retried", httpStatus(http.StatusServiceUnavailable), 3},
+		{"rate limit retried", nodeError{code: -32005, message: "limit exceeded"}, 3},
+		{"endpoint error not retried", httpStatus(http.StatusUnauthorized), 1},
+		{"node error not retried", nodeError{message: "execution reverted"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newFakeNode(t)
+			node.handle("eth_getBalance", func([]json.RawMessage) (any, error) { return nil, tt.err })
+
+			pool := dialTestPool(t, PoolOptions{Retry: RetryPolicy{MaxAttempts: 3}}, node)
+
+			if _, err := pool.BalanceAt(context.Background(), common.HexToAddress("0x1"), nil); err == nil {
+				t.Fatal("BalanceAt succeeded, want error")
+			}
+			if n := node.count("eth_getBalance"); n != tt.wantCalls {
+				t.Errorf("eth_getBalance called %d times, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
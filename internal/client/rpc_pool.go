@@ -0,0 +1,546 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"golang.org/x/time/rate"
+)
+
+// Endpoint — RPC узел; меньший Priority означает более предпочтительный узел.
+type Endpoint struct {
+	URL      string
+	Priority int
+}
+
+// PoolOptions — настройки пула RPC узлов.
+type PoolOptions struct {
+	// Интервал фоновой проверки высоты блока на узлах; 0 — без фоновой проверки.
+	HealthCheckInterval time.Duration
+	// На сколько блоков узел может отставать от лучшего, прежде чем считаться устаревшим.
+	MaxBlockLag uint64
+	// Сколько узлов должны совпасть в ответе для BalanceAt и TransactionReceipt; <= 1 — без кворума.
+	ReadQuorum int
+	// Отправлять подписанную транзакцию на все узлы сразу.
+	BroadcastAll bool
+	// Лимит запросов в секунду на каждый узел; 0 — без лимита.
+	RequestsPerSecond float64
+	// Сколько запросов можно отправить на узел разом сверх лимита.
+	Burst int
+	// Повторы при временных сбоях узлов.
+	Retry RetryPolicy
+	// Таймаут одного запроса к узлу; 0 — без таймаута (только отмена контекста запуска).
+	CallTimeout time.Duration
+	// Таймаут отправки транзакции на узел; 0 — как CallTimeout.
+	SendTimeout time.Duration
+}
+
+type endpoint struct {
+	url      string
+	priority int
+	client   *ethclient.Client
+	limiter  *rate.Limiter
+
+	mu        sync.Mutex
+	healthy   bool
+	lastBlock uint64
+	lastErr   error
+}
+
+// rpcPool повторяет подмножество методов ethclient.Client, раскладывая вызовы по узлам:
+// запросы идут на самый приоритетный здоровый узел и переключаются на следующий при ошибке узла.
+type rpcPool struct {
+	endpoints []*endpoint
+	opts      PoolOptions
+}
+
+func dialPool(ctx context.Context, endpoints []Endpoint, opts PoolOptions) (*rpcPool, error) {
+	pool := &rpcPool{opts: opts}
+
+	for _, e := range endpoints {
+		if strings.TrimSpace(e.URL) == "" {
+			continue
+		}
+
+		dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		cl, err := ethclient.DialContext(dialCtx, e.URL)
+		cancel()
+		if err != nil {
+			log.Printf("[WARN] error connecting to RPC %s: %v", e.URL, err)
+			continue
+		}
+
+		var limiter *rate.Limiter
+		if opts.RequestsPerSecond > 0 {
+			limiter = rate.NewLimiter(rate.Limit(opts.RequestsPerSecond), max(opts.Burst, 1))
+		}
+
+		pool.endpoints = append(pool.endpoints, &endpoint{
+			url:      e.URL,
+			priority: e.Priority,
+			client:   cl,
+			limiter:  limiter,
+			healthy:  true,
+		})
+	}
+
+	if len(pool.endpoints) == 0 {
+		return nil, errors.New("no RPC endpoint is reachable")
+	}
+
+	sort.SliceStable(pool.endpoints, func(i, j int) bool {
+		return pool.endpoints[i].priority < pool.endpoints[j].priority
+	})
+
+	pool.checkHealth(ctx)
+
+	// HTTP узел подключается без запроса, поэтому недоступные узлы видны только после первой проверки.
+	// Кворум, больший числа здоровых узлов, не соберется ни на одном чтении
+	if healthy := pool.healthyCount(); opts.ReadQuorum > healthy {
+		return nil, fmt.Errorf("read quorum %d exceeds %d healthy RPC endpoints", opts.ReadQuorum, healthy)
+	}
+	if opts.HealthCheckInterval > 0 {
+		go pool.healthLoop(ctx)
+	}
+
+	return pool, nil
+}
+
+func (p *rpcPool) healthLoop(ctx context.Context) {
+	ticker := time.NewTicker(p.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.checkHealth(ctx)
+		}
+	}
+}
+
+// checkHealth опрашивает высоту блока на всех узлах и помечает недоступные и отстающие.
+func (p *rpcPool) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			var block uint64
+			err := e.wait(checkCtx)
+			if err == nil {
+				block, err = e.client.BlockNumber(checkCtx)
+			}
+
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			if err != nil {
+				e.healthy, e.lastErr = false, err
+				return
+			}
+			e.lastBlock, e.lastErr = block, nil
+		}(e)
+	}
+	wg.Wait()
+
+	var best uint64
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		if e.lastErr == nil && e.lastBlock > best {
+			best = e.lastBlock
+		}
+		e.mu.Unlock()
+	}
+
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		wasHealthy := e.healthy
+		e.healthy = e.lastErr == nil && e.lastBlock+p.opts.MaxBlockLag >= best
+
+		switch {
+		case wasHealthy && !e.healthy && e.lastErr != nil:
+			log.Printf("[WARN] RPC %s is down: %v", e.url, e.lastErr)
+		case wasHealthy && !e.healthy:
+			log.Printf("[WARN] RPC %s is stale: block %d, best %d", e.url, e.lastBlock, best)
+		case !wasHealthy && e.healthy:
+			log.Printf("[INFO] RPC %s is healthy again", e.url)
+		}
+		e.mu.Unlock()
+	}
+}
+
+func (p *rpcPool) healthyCount() int {
+	n := 0
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		if e.healthy {
+			n++
+		}
+		e.mu.Unlock()
+	}
+
+	return n
+}
+
+// ordered возвращает узлы в порядке обхода: сначала здоровые, затем остальные, внутри — по приоритету.
+func (p *rpcPool) ordered() []*endpoint {
+	healthy := make([]*endpoint, 0, len(p.endpoints))
+	var unhealthy []*endpoint
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		ok := e.healthy
+		e.mu.Unlock()
+
+		if ok {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// wait выдерживает лимит запросов узла.
+func (e *endpoint) wait(ctx context.Context) error {
+	if e.limiter == nil {
+		return nil
+	}
+
+	return e.limiter.Wait(ctx)
+}
+
+func (e *endpoint) markFailed(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.healthy {
+		log.Printf("[WARN] RPC %s failed, switching to next endpoint: %v", e.url, err)
+	}
+	e.healthy, e.lastErr = false, err
+}
+
+// poolCall выполняет fn на узлах пула по очереди, пока не получит ответ, не являющийся сбоем узла.
+// Если все узлы дали временный сбой, обход повторяется с экспоненциальным backoff.
+func poolCall[T any](ctx context.Context, p *rpcPool, timeout time.Duration, fn func(context.Context, *ethclient.Client) (T, error)) (T, error) {
+	var (
+		res T
+		err error
+	)
+
+	attempts := max(p.opts.Retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		transient := false
+		for _, e := range p.ordered() {
+			if err = e.wait(ctx); err != nil {
+				return res, err
+			}
+
+			res, err = callWithTimeout(ctx, timeout, e.client, fn)
+			if ctx.Err() != nil {
+				// Запуск отменен — не переключаемся на другие узлы
+				return res, ctx.Err()
+			}
+			if !isEndpointError(err) {
+				return res, err
+			}
+
+			e.markFailed(err)
+			transient = transient || classifyError(err) == classTransient
+		}
+
+		if !transient || attempt >= attempts {
+			break
+		}
+
+		if err := p.sleepBeforeRetry(ctx, attempt, attempts, err); err != nil {
+			return res, err
+		}
+	}
+
+	return res, fmt.Errorf("all RPC endpoints failed: %w", err)
+}
+
+// callWithTimeout ограничивает один запрос к узлу таймаутом; истекший таймаут считается временным сбоем узла.
+func callWithTimeout[T any](ctx context.Context, timeout time.Duration, c *ethclient.Client, fn func(context.Context, *ethclient.Client) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(ctx, c)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return fn(callCtx, c)
+}
+
+func (p *rpcPool) sendTimeout() time.Duration {
+	if p.opts.SendTimeout > 0 {
+		return p.opts.SendTimeout
+	}
+
+	return p.opts.CallTimeout
+}
+
+func (p *rpcPool) sleepBeforeRetry(ctx context.Context, attempt, attempts int, cause error) error {
+	delay := p.opts.Retry.backoff(attempt)
+	log.Printf("[WARN] all RPC endpoints failed (attempt %d/%d), retrying in %s: %v", attempt, attempts, delay, cause)
+
+	return sleepCtx(ctx, delay)
+}
+
+// quorumCall опрашивает узлы параллельно и возвращает ответ, совпавший минимум на ReadQuorum узлах.
+func quorumCall[T any](ctx context.Context, p *rpcPool, timeout time.Duration, fn func(context.Context, *ethclient.Client) (T, error), key func(T, error) string) (T, error) {
+	type result struct {
+		res T
+		err error
+	}
+
+	endpoints := p.ordered()
+	results := make([]result, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			if err := e.wait(ctx); err != nil {
+				results[i] = result{err: err}
+				return
+			}
+
+			res, err := callWithTimeout(ctx, timeout, e.client, fn)
+			if isEndpointError(err) && ctx.Err() == nil {
+				e.markFailed(err)
+			}
+			results[i] = result{res, err}
+		}(i, e)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	votes := make(map[string]int)
+	var lastErr error
+	for _, r := range results {
+		if isEndpointError(r.err) {
+			lastErr = r.err
+			continue
+		}
+
+		k := key(r.res, r.err)
+		votes[k]++
+		if votes[k] >= p.opts.ReadQuorum {
+			return r.res, r.err
+		}
+	}
+
+	var zero T
+	if lastErr != nil {
+		return zero, fmt.Errorf("no quorum of %d RPC endpoints (votes %v): %w", p.opts.ReadQuorum, votes, lastErr)
+	}
+	return zero, fmt.Errorf("no quorum of %d RPC endpoints (votes %v)", p.opts.ReadQuorum, votes)
+}
+
+func (p *rpcPool) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	fn := func(ctx context.Context, c *ethclient.Client) (*big.Int, error) {
+		return c.BalanceAt(ctx, account, blockNumber)
+	}
+	if p.opts.ReadQuorum <= 1 {
+		return poolCall(ctx, p, p.opts.CallTimeout, fn)
+	}
+
+	return quorumCall(ctx, p, p.opts.CallTimeout, fn, func(balance *big.Int, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return balance.String()
+	})
+}
+
+func (p *rpcPool) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	fn := func(ctx context.Context, c *ethclient.Client) (*types.Receipt, error) {
+		return c.TransactionReceipt(ctx, txHash)
+	}
+	if p.opts.ReadQuorum <= 1 {
+		return poolCall(ctx, p, p.opts.CallTimeout, fn)
+	}
+
+	return quorumCall(ctx, p, p.opts.CallTimeout, fn, func(receipt *types.Receipt, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("%s/%d", receipt.BlockHash.Hex(), receipt.Status)
+	})
+}
+
+// SendTransaction отправляет транзакцию на первый доступный узел или, с BroadcastAll, сразу на все.
+//
+// Если попытка завершилась сбоем узла (например, таймаутом), транзакция могла дойти до сети и даже
+// попасть в блок. Тогда «nonce too low» на следующей попытке не отказ, а возможный признак успеха
+// первой: возвращается ErrSendUnconfirmed, и транзакцию нужно ждать по ее хэшу.
+func (p *rpcPool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	maybeSent := false
+
+	if !p.opts.BroadcastAll {
+		_, err := poolCall(ctx, p, p.sendTimeout(), func(ctx context.Context, c *ethclient.Client) (struct{}, error) {
+			err := sendTx(ctx, c, tx)
+			maybeSent = maybeSent || isEndpointError(err)
+			return struct{}{}, err
+		})
+		return sentEarlier(err, maybeSent)
+	}
+
+	attempts := max(p.opts.Retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		transient, err := p.broadcast(ctx, tx)
+		if err == nil || !transient || attempt >= attempts {
+			return sentEarlier(err, maybeSent)
+		}
+		maybeSent = true
+
+		if err := p.sleepBeforeRetry(ctx, attempt, attempts, err); err != nil {
+			return err
+		}
+	}
+}
+
+// broadcast отправляет транзакцию на все узлы параллельно; transient — все отказы были временными сбоями.
+func (p *rpcPool) broadcast(ctx context.Context, tx *types.Transaction) (bool, error) {
+	endpoints := p.ordered()
+	errs := make([]error, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			if errs[i] = e.wait(ctx); errs[i] != nil {
+				return
+			}
+
+			_, errs[i] = callWithTimeout(ctx, p.sendTimeout(), e.client, func(ctx context.Context, c *ethclient.Client) (struct{}, error) {
+				return struct{}{}, sendTx(ctx, c, tx)
+			})
+			if isEndpointError(errs[i]) && ctx.Err() == nil {
+				e.markFailed(errs[i])
+			}
+		}(i, e)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	// Достаточно одного принявшего узла; иначе предпочитаем ответ сети (nonce too low и т.п.) сбою узла
+	var endpointErr, nodeErr error
+	transient := false
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			return false, nil
+		case isEndpointError(err):
+			endpointErr = err
+			transient = transient || classifyError(err) == classTransient
+		default:
+			nodeErr = err
+		}
+	}
+
+	if nodeErr != nil {
+		return false, nodeErr
+	}
+	return transient, fmt.Errorf("all RPC endpoints failed: %w", endpointErr)
+}
+
+// sentEarlier превращает «nonce too low» в ErrSendUnconfirmed, если более ранняя попытка могла дойти до сети.
+func sentEarlier(err error, maybeSent bool) error {
+	if maybeSent && isNonceTooLow(err) {
+		return fmt.Errorf("%w: an earlier attempt may have been accepted: %v", ErrSendUnconfirmed, err)
+	}
+
+	return err
+}
+
+// sendTx отправляет транзакцию на узел. Ответ «already known» означает, что узел уже принял ее —
+// например, после таймаута первой попытки, которая все же дошла, — и считается успешной отправкой.
+func sendTx(ctx context.Context, c *ethclient.Client, tx *types.Transaction) error {
+	err := c.SendTransaction(ctx, tx)
+	if isAlreadyKnown(err) {
+		return nil
+	}
+
+	return err
+}
+
+func isAlreadyKnown(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
+
+func (p *rpcPool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, p.opts.CallTimeout, func(ctx context.Context, c *ethclient.Client) ([]byte, error) {
+		return c.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+func (p *rpcPool) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return poolCall(ctx, p, p.opts.CallTimeout, func(ctx context.Context, c *ethclient.Client) (uint64, error) { return c.PendingNonceAt(ctx, account) })
+}
+
+func (p *rpcPool) NetworkID(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, p.opts.CallTimeout, func(ctx context.Context, c *ethclient.Client) (*big.Int, error) { return c.NetworkID(ctx) })
+}
+
+func (p *rpcPool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return poolCall(ctx, p, p.opts.CallTimeout, func(ctx context.Context, c *ethclient.Client) (*types.Header, error) {
+		return c.HeaderByNumber(ctx, number)
+	})
+}
+
+func (p *rpcPool) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, p.opts.CallTimeout, func(ctx context.Context, c *ethclient.Client) (*big.Int, error) { return c.SuggestGasTipCap(ctx) })
+}
+
+func (p *rpcPool) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return poolCall(ctx, p, p.opts.CallTimeout, func(ctx context.Context, c *ethclient.Client) (*ethereum.FeeHistory, error) {
+		return c.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+func (p *rpcPool) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, p.opts.CallTimeout, func(ctx context.Context, c *ethclient.Client) ([]byte, error) {
+		return c.CodeAt(ctx, account, blockNumber)
+	})
+}
+
+func (p *rpcPool) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return poolCall(ctx, p, p.opts.CallTimeout, func(ctx context.Context, c *ethclient.Client) (uint64, error) { return c.EstimateGas(ctx, msg) })
+}
+
+func (p *rpcPool) BlockNumber(ctx context.Context) (uint64, error) {
+	return poolCall(ctx, p, p.opts.CallTimeout, func(ctx context.Context, c *ethclient.Client) (uint64, error) { return c.BlockNumber(ctx) })
+}
@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// balanceNode отвечает на eth_getBalance значением balance или ошибкой err.
+func balanceNode(t *testing.T, balance int64, err error) *fakeNode {
+	n := newFakeNode(t)
+	n.handle("eth_getBalance", func([]json.RawMessage) (any, error) {
+		if err != nil {
+			return nil, err
+		}
+		return (*hexutil.Big)(big.NewInt(balance)), nil
+	})
+
+	return n
+}
+
+func TestQuorumBalance(t *testing.T) {
+	down := httpStatus(http.StatusServiceUnavailable)
+
+	tests := []struct {
+		name    string
+		quorum  int
+		nodes   []func(t *testing.T) *fakeNode
+		want    int64
+		wantErr string
+	}{
+		{
+			name:   "all agree",
+			quorum: 2,
+			nodes: []func(t *testing.T) *fakeNode{
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+			},
+			want: 5,
+		},
+		{
+			name:   "majority agrees",
+			quorum: 2,
+			nodes: []func(t *testing.T) *fakeNode{
+				func(t *testing.T) *fakeNode { return balanceNode(t, 4, nil) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+			},
+			want: 5,
+		},
+		{
+			name:   "disagreement",
+			quorum: 2,
+			nodes: []func(t *testing.T) *fakeNode{
+				func(t *testing.T) *fakeNode { return balanceNode(t, 4, nil) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 6, nil) },
+			},
+			wantErr: "no quorum of 2",
+		},
+		{
+			name:   "failed endpoint does not vote",
+			quorum: 2,
+			nodes: []func(t *testing.T) *fakeNode{
+				func(t *testing.T) *fakeNode { return balanceNode(t, 0, down) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+			},
+			want: 5,
+		},
+		{
+			name:   "quorum lost with a failed endpoint",
+			quorum: 3,
+			nodes: []func(t *testing.T) *fakeNode{
+				func(t *testing.T) *fakeNode { return balanceNode(t, 0, down) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+				func(t *testing.T) *fakeNode { return balanceNode(t, 5, nil) },
+			},
+			wantErr: "no quorum of 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := make([]*fakeNode, len(tt.nodes))
+			for i, mk := range tt.nodes {
+				nodes[i] = mk(t)
+			}
+
+			pool := dialTestPool(t, PoolOptions{ReadQuorum: tt.quorum}, nodes...)
+
+			got, err := pool.BalanceAt(context.Background(), common.HexToAddress("0x1"), nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("BalanceAt = %v, %v; want error %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BalanceAt: %v", err)
+			}
+			if got.Int64() != tt.want {
+				t.Errorf("BalanceAt = %s, want %d", got, tt.want)
+			}
+
+			for i, n := range nodes {
+				if n.count("eth_getBalance") != 1 {
+					t.Errorf("node %d asked %d times, want 1", i, n.count("eth_getBalance"))
+				}
+			}
+		})
+	}
+}
+
+func TestQuorumReceiptNotFound(t *testing.T) {
+	hash := common.HexToHash("0xabc")
+	mined := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		GasUsed:           21000,
+		Logs:              []*types.Log{},
+		TxHash:            hash,
+		BlockHash:         common.HexToHash("0xb1"),
+		BlockNumber:       big.NewInt(7),
+	}
+
+	tests := []struct {
+		name         string
+		receipts     []*types.Receipt
+		wantNotFound bool
+	}{
+		{"not found on quorum", []*types.Receipt{nil, nil, mined}, true},
+		{"mined on quorum", []*types.Receipt{nil, mined, mined}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []*fakeNode
+			for _, r := range tt.receipts {
+				n := newFakeNode(t)
+				n.handle("eth_getTransactionReceipt", func([]json.RawMessage) (any, error) { return r, nil })
+				nodes = append(nodes, n)
+			}
+
+			pool := dialTestPool(t, PoolOptions{ReadQuorum: 2}, nodes...)
+
+			receipt, err := pool.TransactionReceipt(context.Background(), hash)
+			if tt.wantNotFound {
+				if !errors.Is(err, ethereum.NotFound) {
+					t.Fatalf("TransactionReceipt = %v, %v; want NotFound", receipt, err)
+				}
+				return
+			}
+			if err != nil || receipt.TxHash != hash {
+				t.Fatalf("TransactionReceipt = %v, %v", receipt, err)
+			}
+		})
+	}
+}
+
+func TestDialPoolQuorumAboveHealthyEndpoints(t *testing.T) {
+	healthy := newFakeNode(t)
+	down := newFakeNode(t)
+	down.handle("eth_blockNumber", func([]json.RawMessage) (any, error) { return nil, httpStatus(http.StatusServiceUnavailable) })
+
+	endpoints := []Endpoint{{URL: healthy.URL()}, {URL: down.URL(), Priority: 1}}
+
+	if _, err := dialPool(context.Background(), endpoints, PoolOptions{ReadQuorum: 2}); err == nil || !strings.Contains(err.Error(), "read quorum 2 exceeds 1 healthy") {
+		t.Fatalf("dialPool error = %v, want quorum error", err)
+	}
+
+	if _, err := dialPool(context.Background(), endpoints, PoolOptions{ReadQuorum: 1}); err != nil {
+		t.Fatalf("dialPool with quorum 1: %v", err)
+	}
+}
+
+func TestPoolCallFailover(t *testing.T) {
+	tests := []struct {
+		name         string
+		firstErr     error
+		wantErr      string
+		wantFallback bool
+	}{
+		{"endpoint error switches node", httpStatus(http.StatusServiceUnavailable), "", true},
+		{"rate limit switches node", nodeError{code: -32005, message: "limit exceeded"}, "", true},
+		{"node error is returned as is", nodeError{message: "execution reverted"}, "execution reverted", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := newFakeNode(t), newFakeNode(t)
+			first.handle("eth_call", func([]json.RawMessage) (any, error) { return nil, tt.firstErr })
+			second.handle("eth_call", func([]json.RawMessage) (any, error) { return "0x01", nil })
+
+			pool := dialTestPool(t, PoolOptions{}, first, second)
+
+			to := common.HexToAddress("0x1000")
+			res, err := pool.CallContract(context.Background(), ethereum.CallMsg{To: &to}, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CallContract = %x, %v; want %q", res, err, tt.wantErr)
+				}
+			} else if err != nil || len(res) != 1 || res[0] != 1 {
+				t.Fatalf("CallContract = %x, %v", res, err)
+			}
+
+			if got := second.count("eth_call") > 0; got != tt.wantFallback {
+				t.Errorf("fallback node used: %v, want %v", got, tt.wantFallback)
+			}
+		})
+	}
+}
+
+func TestBroadcastAll(t *testing.T) {
+	tx := signTestTx(t, newTestSigner(t), 0, 100, 1000)
+	down := httpStatus(http.StatusServiceUnavailable)
+
+	tests := []struct {
+		name    string
+		errs    []error
+		wantErr string
+	}{
+		{"one node accepts", []error{nodeError{message: "nonce too low"}, nil}, ""},
+		{"already known counts as accepted", []error{nodeError{message: "already known"}, down}, ""},
+		{"node answer preferred over endpoint failure", []error{down, nodeError{message: "insufficient funds"}}, "insufficient funds"},
+		{"all endpoints failed", []error{down, down}, "all RPC endpoints failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []*fakeNode
+			for _, sendErr := range tt.errs {
+				n := newFakeNode(t)
+				n.handle("eth_sendRawTransaction", func([]json.RawMessage) (any, error) {
+					if sendErr != nil {
+						return nil, sendErr
+					}
+					return tx.Hash(), nil
+				})
+				nodes = append(nodes, n)
+			}
+
+			pool := dialTestPool(t, PoolOptions{BroadcastAll: true}, nodes...)
+
+			err := pool.SendTransaction(context.Background(), tx)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("SendTransaction: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("SendTransaction error = %v, want %q", err, tt.wantErr)
+			}
+
+			for i, n := range nodes {
+				if n.count("eth_sendRawTransaction") != 1 {
+					t.Errorf("node %d got %d sends, want 1", i, n.count("eth_sendRawTransaction"))
+				}
+			}
+		})
+	}
+}
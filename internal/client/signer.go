@@ -0,0 +1,245 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Signer подписывает транзакции одного адреса. Ключ может жить в памяти процесса,
+// в keystore файле или во внешнем сервисе подписи (Clef, web3signer).
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// KeySigner подписывает ключом из памяти процесса.
+type KeySigner struct {
+	key  *ecdsa.PrivateKey
+	addr common.Address
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key, addr: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.addr
+}
+
+func (s *KeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// KeystoreSigner подписывает ключом из V3 keystore файла. Ключ расшифровывается только на время
+// подписи и сразу затирается, поэтому каждая подпись стоит одного вызова scrypt.
+type KeystoreSigner struct {
+	path       string
+	passphrase string
+	addr       common.Address
+}
+
+// NewKeystoreSigner проверяет, что файл расшифровывается паролем, и запоминает адрес ключа.
+func NewKeystoreSigner(path, passphrase string) (*KeystoreSigner, error) {
+	s := &KeystoreSigner{path: path, passphrase: passphrase}
+
+	key, err := s.decrypt()
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	s.addr = key.Address
+	return s, nil
+}
+
+func (s *KeystoreSigner) Address() common.Address {
+	return s.addr
+}
+
+func (s *KeystoreSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	key, err := s.decrypt()
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), key.PrivateKey)
+}
+
+func (s *KeystoreSigner) decrypt() (*keystore.Key, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file %s: %w", s.path, err)
+	}
+
+	key, err := keystore.DecryptKey(data, s.passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore file %s: %w", s.path, err)
+	}
+
+	return key, nil
+}
+
+func zeroKey(key *ecdsa.PrivateKey) {
+	b := key.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// RemoteSigner подписывает через eth_signTransaction внешнего сервиса (Clef, web3signer):
+// ключ не покидает сервис подписи. Подписанная транзакция сверяется с запрошенной.
+type RemoteSigner struct {
+	client *rpc.Client
+	addr   common.Address
+}
+
+type remoteTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// DialRemoteSigner подключается к сервису подписи по JSON-RPC.
+func DialRemoteSigner(ctx context.Context, url string) (*rpc.Client, error) {
+	cl, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer %s: %w", url, err)
+	}
+
+	return cl, nil
+}
+
+// RemoteAccounts возвращает адреса, которыми управляет сервис подписи:
+// eth_accounts (web3signer) или account_list (Clef).
+func RemoteAccounts(ctx context.Context, cl *rpc.Client) ([]common.Address, error) {
+	var addrs []common.Address
+	err := cl.CallContext(ctx, &addrs, "eth_accounts")
+	if err != nil {
+		if clefErr := cl.CallContext(ctx, &addrs, "account_list"); clefErr != nil {
+			return nil, fmt.Errorf("failed to list remote signer accounts: %w", err)
+		}
+	}
+
+	return addrs, nil
+}
+
+func NewRemoteSigner(cl *rpc.Client, addr common.Address) *RemoteSigner {
+	return &RemoteSigner{client: cl, addr: addr}
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.addr
+}
+
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := remoteTxArgs{
+		From:                 s.addr,
+		To:                   tx.To(),
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Value:                (*hexutil.Big)(tx.Value()),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Data:                 tx.Data(),
+		ChainID:              (*hexutil.Big)(chainID),
+	}
+
+	var res json.RawMessage
+	if err := s.client.CallContext(ctx, &res, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("remote signer rejected transaction: %w", err)
+	}
+
+	raw, err := decodeSignResult(res)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode remotely signed transaction: %w", err)
+	}
+
+	if err := checkSigned(tx, signed, s.addr, chainID); err != nil {
+		return nil, err
+	}
+
+	return signed, nil
+}
+
+// decodeSignResult принимает оба формата ответа: hex строку (web3signer) и {raw, tx} (Clef).
+func decodeSignResult(res json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(res, &raw); err == nil {
+		return raw, nil
+	}
+
+	var clef struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(res, &clef); err != nil || len(clef.Raw) == 0 {
+		return nil, fmt.Errorf("unexpected remote signer response: %s", res)
+	}
+
+	return clef.Raw, nil
+}
+
+// checkSigned сверяет подписанную транзакцию с запрошенной: сервис подписи не должен менять ее поля.
+func checkSigned(want, got *types.Transaction, from common.Address, chainID *big.Int) error {
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), got)
+	if err != nil {
+		return fmt.Errorf("invalid remote signature: %w", err)
+	}
+
+	var mismatch []string
+	if sender != from {
+		mismatch = append(mismatch, "sender")
+	}
+	if got.Nonce() != want.Nonce() {
+		mismatch = append(mismatch, "nonce")
+	}
+	if got.Gas() != want.Gas() {
+		mismatch = append(mismatch, "gas")
+	}
+	if (got.To() == nil) != (want.To() == nil) || (got.To() != nil && *got.To() != *want.To()) {
+		mismatch = append(mismatch, "to")
+	}
+	if got.Value().Cmp(want.Value()) != 0 {
+		mismatch = append(mismatch, "value")
+	}
+	if !bytes.Equal(got.Data(), want.Data()) {
+		mismatch = append(mismatch, "data")
+	}
+	if got.GasFeeCap().Cmp(want.GasFeeCap()) != 0 || got.GasTipCap().Cmp(want.GasTipCap()) != 0 {
+		mismatch = append(mismatch, "fees")
+	}
+	if got.ChainId().Cmp(chainID) != 0 {
+		mismatch = append(mismatch, "chainId")
+	}
+
+	if len(mismatch) > 0 {
+		return errors.New("remotely signed transaction differs from request: " + strings.Join(mismatch, ", "))
+	}
+
+	return nil
+}
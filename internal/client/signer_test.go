@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// signerNode — сервис подписи: подписывает запрошенную транзакцию ключом key в сети chainID,
+// предварительно изменив ее через tamper.
+func signerNode(t *testing.T, key *KeySigner, chainID *big.Int, tamper func(*types.DynamicFeeTx), clef bool) *fakeNode {
+	n := newFakeNode(t)
+	n.handle("eth_signTransaction", func(params []json.RawMessage) (any, error) {
+		var args remoteTxArgs
+		if err := json.Unmarshal(params[0], &args); err != nil {
+			return nil, err
+		}
+
+		inner := &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: (*big.Int)(args.MaxPriorityFeePerGas),
+			GasFeeCap: (*big.Int)(args.MaxFeePerGas),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     (*big.Int)(args.Value),
+			Data:      args.Data,
+		}
+		if tamper != nil {
+			tamper(inner)
+		}
+
+		signed, err := key.SignTx(context.Background(), types.NewTx(inner), chainID)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		if clef {
+			return map[string]any{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+		}
+		return hexutil.Bytes(raw), nil
+	})
+
+	return n
+}
+
+func dialTestSigner(t *testing.T, n *fakeNode) *rpc.Client {
+	t.Helper()
+
+	cl, err := DialRemoteSigner(context.Background(), n.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cl.Close)
+
+	return cl
+}
+
+func TestRemoteSignerChecksSignedTx(t *testing.T) {
+	tests := []struct {
+		name    string
+		chainID *big.Int
+		tamper  func(*types.DynamicFeeTx)
+		other   bool // подписывает чужой ключ
+		clef    bool
+		wantErr string
+	}{
+		{name: "web3signer response"},
+		{name: "clef response", clef: true},
+		{name: "wrong chain", chainID: big.NewInt(1), wantErr: "invalid remote signature"},
+		{name: "wrong sender", other: true, wantErr: "sender"},
+		{name: "changed nonce", tamper: func(tx *types.DynamicFeeTx) { tx.Nonce++ }, wantErr: "nonce"},
+		{name: "changed recipient", tamper: func(tx *types.DynamicFeeTx) { tx.To = &common.Address{1} }, wantErr: "to"},
+		{name: "changed value", tamper: func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(1e18) }, wantErr: "value"},
+		{name: "changed payload", tamper: func(tx *types.DynamicFeeTx) { tx.Data = []byte{0xbe, 0xef} }, wantErr: "data"},
+		{name: "changed fees", tamper: func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(1e12) }, wantErr: "fees"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := newTestSigner(t)
+			signingKey := key
+			if tt.other {
+				signingKey = newTestSigner(t)
+			}
+			chainID := testChainID
+			if tt.chainID != nil {
+				chainID = tt.chainID
+			}
+
+			node := signerNode(t, signingKey, chainID, tt.tamper, tt.clef)
+			signer := NewRemoteSigner(dialTestSigner(t, node), key.Address())
+
+			// Шаблон подписывается локально только ради полей; сервису уходят поля без подписи
+			want := signTestTx(t, key, 3, 100, 1000)
+
+			signed, err := signer.SignTx(context.Background(), want, testChainID)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SignTx error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SignTx: %v", err)
+			}
+			if signed.Hash() != want.Hash() {
+				t.Errorf("signed %s, want %s", signed.Hash().Hex(), want.Hash().Hex())
+			}
+		})
+	}
+}
+
+func TestRemoteSignerRejection(t *testing.T) {
+	node := newFakeNode(t)
+	node.handle("eth_signTransaction", func([]json.RawMessage) (any, error) {
+		return nil, nodeError{message: "request denied"}
+	})
+
+	key := newTestSigner(t)
+	signer := NewRemoteSigner(dialTestSigner(t, node), key.Address())
+
+	if _, err := signer.SignTx(context.Background(), signTestTx(t, key, 0, 100, 1000), testChainID); err == nil || !strings.Contains(err.Error(), "request denied") {
+		t.Fatalf("SignTx error = %v, want rejection", err)
+	}
+}
+
+func TestRemoteAccounts(t *testing.T) {
+	addr := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	web3signer := newFakeNode(t)
+	web3signer.handle("eth_accounts", func([]json.RawMessage) (any, error) { return []common.Address{addr}, nil })
+
+	// Clef не поддерживает eth_accounts
+	clef := newFakeNode(t)
+	clef.handle("account_list", func([]json.RawMessage) (any, error) { return []common.Address{addr}, nil })
+
+	for name, n := range map[string]*fakeNode{"web3signer": web3signer, "clef": clef} {
+		got, err := RemoteAccounts(context.Background(), dialTestSigner(t, n))
+		if err != nil || len(got) != 1 || got[0] != addr {
+			t.Errorf("%s: RemoteAccounts = %v, %v; want [%s]", name, got, err, addr.Hex())
+		}
+	}
+
+	if _, err := RemoteAccounts(context.Background(), dialTestSigner(t, newFakeNode(t))); err == nil {
+		t.Error("RemoteAccounts without account methods succeeded")
+	}
+}
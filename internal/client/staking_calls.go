@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	client "ms/internal/client/consts"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (c *EthClient) GetDelegator(ctx context.Context, to string, validatorID uint64, delegator common.Address) (Delegator, error) {
+	var res Delegator
+	if err := c.callStaking(ctx, to, "getDelegator", &res, validatorID, delegator); err != nil {
+		return Delegator{}, fmt.Errorf("failed to get delegator: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *EthClient) GetWithdrawalRequest(ctx context.Context, to string, validatorID uint64, delegator common.Address, withdrawID uint8) (WithdrawalRequest, error) {
+	var res WithdrawalRequest
+	if err := c.callStaking(ctx, to, "getWithdrawalRequest", &res, validatorID, delegator, withdrawID); err != nil {
+		return WithdrawalRequest{}, fmt.Errorf("failed to get withdrawal request: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *EthClient) GetEpoch(ctx context.Context, to string) (Epoch, error) {
+	var res struct {
+		Epoch              uint64
+		InEpochDelayPeriod bool
+	}
+	if err := c.callStaking(ctx, to, "getEpoch", &res); err != nil {
+		return Epoch{}, fmt.Errorf("failed to get epoch: %w", err)
+	}
+
+	return Epoch{
+		Number:            res.Epoch,
+		InEpochDelayPhase: res.InEpochDelayPeriod,
+	}, nil
+}
+
+func (c *EthClient) GetValidator(ctx context.Context, to string, validatorID uint64) (Validator, error) {
+	var res Validator
+	if err := c.callStaking(ctx, to, "getValidator", &res, validatorID); err != nil {
+		return Validator{}, fmt.Errorf("failed to get validator: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetDelegations возвращает ID всех валидаторов, которым делегировал delegator, проходя по страницам ответа.
+func (c *EthClient) GetDelegations(ctx context.Context, to string, delegator common.Address) ([]uint64, error) {
+	var (
+		validators []uint64
+		startID    uint64
+	)
+
+	for {
+		var page struct {
+			IsDone    bool
+			NextValId uint64
+			ValIds    []uint64
+		}
+		if err := c.callStaking(ctx, to, "getDelegations", &page, delegator, startID); err != nil {
+			return nil, fmt.Errorf("failed to get delegations: %w", err)
+		}
+
+		validators = append(validators, page.ValIds...)
+
+		if page.IsDone {
+			return validators, nil
+		}
+
+		// Страница без продвижения курсора повторялась бы бесконечно
+		if page.NextValId <= startID {
+			return nil, fmt.Errorf("failed to get delegations: next validator ID %d does not advance past %d", page.NextValId, startID)
+		}
+
+		startID = page.NextValId
+	}
+}
+
+// GetValidatorSet возвращает ID всех зарегистрированных валидаторов (execution validator set).
+func (c *EthClient) GetValidatorSet(ctx context.Context, to string) ([]uint64, error) {
+	return c.validatorSet(ctx, to, "getExecutionValidatorSet")
+}
+
+// GetConsensusValidatorSet возвращает ID активных валидаторов текущей эпохи (consensus validator set).
+func (c *EthClient) GetConsensusValidatorSet(ctx context.Context, to string) ([]uint64, error) {
+	return c.validatorSet(ctx, to, "getConsensusValidatorSet")
+}
+
+// validatorSet читает постраничный набор валидаторов методом method.
+func (c *EthClient) validatorSet(ctx context.Context, to, method string) ([]uint64, error) {
+	var (
+		validators []uint64
+		startIndex uint32
+	)
+
+	for {
+		var page struct {
+			IsDone    bool
+			NextIndex uint32
+			ValIds    []uint64
+		}
+		if err := c.callStaking(ctx, to, method, &page, startIndex); err != nil {
+			return nil, fmt.Errorf("failed to get validator set (%s): %w", method, err)
+		}
+
+		validators = append(validators, page.ValIds...)
+
+		if page.IsDone {
+			return validators, nil
+		}
+
+		// Страница без продвижения курсора повторялась бы бесконечно
+		if page.NextIndex <= startIndex {
+			return nil, fmt.Errorf("failed to get validator set (%s): next index %d does not advance past %d", method, page.NextIndex, startIndex)
+		}
+
+		startIndex = page.NextIndex
+	}
+}
+
+// callStaking выполняет eth_call метода стейкинг-контракта и декодирует ответ в out по ABI.
+func (c *EthClient) callStaking(ctx context.Context, to, method string, out interface{}, args ...interface{}) error {
+	data, err := packStaking(method, args...)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.CallCA(ctx, common.HexToAddress(to), data)
+	if err != nil {
+		return err
+	}
+
+	if err := client.StakingABI.UnpackIntoInterface(out, method, res); err != nil {
+		return fmt.Errorf("ошибка декодирования ответа %s: %w", method, err)
+	}
+
+	return nil
+}
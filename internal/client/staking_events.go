@@ -0,0 +1,67 @@
+package client
+
+import (
+	"fmt"
+	client "ms/internal/client/consts"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// StakingEvent — декодированное по ABI событие стейкинг-контракта.
+type StakingEvent struct {
+	Name   string
+	Fields map[string]interface{}
+
+	inputs abi.Arguments
+}
+
+func (e StakingEvent) String() string {
+	parts := make([]string, 0, len(e.inputs))
+	for _, input := range e.inputs {
+		parts = append(parts, fmt.Sprintf("%s=%v", input.Name, e.Fields[input.Name]))
+	}
+
+	return fmt.Sprintf("%s(%s)", e.Name, strings.Join(parts, ", "))
+}
+
+// DecodeStakingEvents декодирует логи стейкинг-контракта из receipt; чужие и неизвестные логи пропускаются.
+func DecodeStakingEvents(contract common.Address, logs []*types.Log) ([]StakingEvent, error) {
+	var events []StakingEvent
+	for _, l := range logs {
+		if l.Address != contract || len(l.Topics) == 0 {
+			continue
+		}
+
+		event, err := client.StakingABI.EventByID(l.Topics[0])
+		if err != nil {
+			continue
+		}
+
+		fields := make(map[string]interface{})
+		if err := event.Inputs.NonIndexed().UnpackIntoMap(fields, l.Data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s event data: %w", event.Name, err)
+		}
+
+		var indexed abi.Arguments
+		for _, input := range event.Inputs {
+			if input.Indexed {
+				indexed = append(indexed, input)
+			}
+		}
+
+		if err := abi.ParseTopicsIntoMap(fields, indexed, l.Topics[1:]); err != nil {
+			return nil, fmt.Errorf("failed to decode %s event topics: %w", event.Name, err)
+		}
+
+		events = append(events, StakingEvent{
+			Name:   event.Name,
+			Fields: fields,
+			inputs: event.Inputs,
+		})
+	}
+
+	return events, nil
+}
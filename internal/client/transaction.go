@@ -2,142 +2,419 @@ package client
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"log"
+	"math/big"
 	client "ms/internal/client/consts"
 	"ms/pkg/utils"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-func (c *EthClient) SendTransaction(ctx context.Context, amount float32, to string, privatekey *ecdsa.PrivateKey, validatorID uint8) error {
-	preparedData, err := c.prepareData(ctx, amount, to, validatorID, privatekey)
+var (
+	ErrTxTimeout  = errors.New("transaction wait timeout")
+	ErrTxReverted = errors.New("transaction failed")
+
+	ErrNonceTooLow = errors.New("nonce too low")
+
+	// Отправка не подтверждена: узел не ответил, но мог принять транзакцию. Nonce остается занятым,
+	// транзакцию нужно ждать как отправленную.
+	ErrSendUnconfirmed = errors.New("transaction send unconfirmed")
+
+	ErrInsufficientBalance = errors.New("insufficient balance")
+)
+
+func (c *EthClient) SendTransaction(ctx context.Context, amount *big.Int, to string, signer Signer, validatorID uint64) error {
+	txData, err := c.CreateDelegateData(validatorID)
 	if err != nil {
-		return fmt.Errorf("failed to prepare data: %v", err)
+		return fmt.Errorf("failed to create delegate data: %v", err)
 	}
 
-	dynamicTx := types.DynamicFeeTx{
-		ChainID:   preparedData.ChainID,
-		Nonce:     preparedData.Nonce,
-		GasTipCap: preparedData.MaxPriorityFeePerGas,
-		GasFeeCap: preparedData.MaxFeePerGas,
-		Gas:       preparedData.GasLimit,
-		To:        preparedData.DestinationAddr,
-		Value:     preparedData.Amount,
-		Data:      preparedData.TxData,
+	return c.sendStakingTx(ctx, amount, to, signer, txData)
+}
+
+func (c *EthClient) Undelegate(ctx context.Context, amount *big.Int, to string, signer Signer, validatorID uint64, withdrawID uint8) error {
+	txData, err := c.CreateUndelegateData(validatorID, amount, withdrawID)
+	if err != nil {
+		return fmt.Errorf("failed to create undelegate data: %v", err)
+	}
+
+	return c.sendStakingTx(ctx, common.Big0, to, signer, txData)
+}
+
+func (c *EthClient) Withdraw(ctx context.Context, to string, signer Signer, validatorID uint64, withdrawID uint8) error {
+	txData, err := c.CreateWithdrawData(validatorID, withdrawID)
+	if err != nil {
+		return fmt.Errorf("failed to create withdraw data: %v", err)
+	}
+
+	return c.sendStakingTx(ctx, common.Big0, to, signer, txData)
+}
+
+func (c *EthClient) Compound(ctx context.Context, to string, signer Signer, validatorID uint64) error {
+	txData, err := c.CreateCompoundData(validatorID)
+	if err != nil {
+		return fmt.Errorf("failed to create compound data: %v", err)
+	}
+
+	return c.sendStakingTx(ctx, common.Big0, to, signer, txData)
+}
+
+func (c *EthClient) ClaimRewards(ctx context.Context, to string, signer Signer, validatorID uint64) error {
+	txData, err := c.CreateClaimRewardsData(validatorID)
+	if err != nil {
+		return fmt.Errorf("failed to create claim rewards data: %v", err)
+	}
+
+	return c.sendStakingTx(ctx, common.Big0, to, signer, txData)
+}
+
+// SignDelegate готовит и подписывает delegate, не отправляя его в сеть.
+func (c *EthClient) SignDelegate(ctx context.Context, amount *big.Int, to string, signer Signer, validatorID uint64) (*types.Transaction, TxPlan, error) {
+	txData, err := c.CreateDelegateData(validatorID)
+	if err != nil {
+		return nil, TxPlan{}, fmt.Errorf("failed to create delegate data: %v", err)
+	}
+
+	signedTx, preparedData, err := c.signStakingTx(ctx, amount, to, signer, txData)
+	if err != nil {
+		return nil, TxPlan{}, err
+	}
+
+	plan := newTxPlan(signedTx, preparedData)
+	plan.ValidatorID = validatorID
+
+	return signedTx, plan, nil
+}
+
+func newTxPlan(signedTx *types.Transaction, preparedData ChainData) TxPlan {
+	return TxPlan{
+		Amount:               preparedData.Amount,
+		Nonce:                preparedData.Nonce,
+		GasLimit:             preparedData.GasLimit,
+		MaxFeePerGas:         preparedData.MaxFeePerGas,
+		MaxPriorityFeePerGas: preparedData.MaxPriorityFeePerGas,
+		EstimatedCost:        new(big.Int).Mul(new(big.Int).SetUint64(preparedData.GasLimit), preparedData.MaxFeePerGas),
+		TxHash:               signedTx.Hash(),
+	}
+}
+
+// SimulateDelegate готовит и подписывает delegate так же, как SendTransaction, но вместо отправки
+// выполняет eth_call с теми же параметрами и возвращает план транзакции.
+func (c *EthClient) SimulateDelegate(ctx context.Context, amount *big.Int, to string, signer Signer, validatorID uint64) (TxPlan, error) {
+	signedTx, plan, err := c.SignDelegate(ctx, amount, to, signer, validatorID)
+	if err != nil {
+		return TxPlan{}, err
+	}
+
+	// Транзакция не уходит в сеть — nonce возвращается менеджеру
+	defer c.releaseNonce(signedTx)
+
+	res, err := c.client.CallContract(ctx, ethereum.CallMsg{
+		From:      signer.Address(),
+		To:        signedTx.To(),
+		Gas:       signedTx.Gas(),
+		GasFeeCap: signedTx.GasFeeCap(),
+		GasTipCap: signedTx.GasTipCap(),
+		Value:     signedTx.Value(),
+		Data:      signedTx.Data(),
+	}, nil)
+	if err != nil {
+		return TxPlan{}, fmt.Errorf("delegate simulation reverted: %v", err)
 	}
 
-	signedTx, err := types.SignTx(types.NewTx(&dynamicTx), types.LatestSignerForChainID(preparedData.ChainID), privatekey)
+	var out struct{ Success bool }
+	if err := client.StakingABI.UnpackIntoInterface(&out, "delegate", res); err != nil {
+		return TxPlan{}, fmt.Errorf("failed to decode delegate simulation result: %v", err)
+	}
+
+	if !out.Success {
+		return TxPlan{}, fmt.Errorf("delegate simulation returned false")
+	}
+
+	return plan, nil
+}
+
+func (c *EthClient) sendStakingTx(ctx context.Context, amount *big.Int, to string, signer Signer, txData []byte) error {
+	signedTx, _, err := c.signStakingTx(ctx, amount, to, signer, txData)
 	if err != nil {
-		return fmt.Errorf("failed to sign transaction: %v", err)
+		return err
+	}
+
+	err = c.BroadcastTransaction(ctx, signedTx)
+	if errors.Is(err, ErrNonceTooLow) {
+		// Менеджер уже пересинхронизирован с сетью — переподписываем со свежим nonce один раз
+		log.Printf("[WARN] %v, re-signing with a fresh nonce", err)
+
+		if signedTx, _, err = c.signStakingTx(ctx, amount, to, signer, txData); err != nil {
+			return err
+		}
+		err = c.BroadcastTransaction(ctx, signedTx)
+	}
+	if errors.Is(err, ErrSendUnconfirmed) {
+		log.Printf("[WARN] %v, waiting for it as sent", err)
+	} else if err != nil {
+		return err
+	}
+
+	_, err = c.WaitForTransaction(ctx, signedTx, signer, nil)
+	return err
+}
+
+func (c *EthClient) BroadcastTransaction(ctx context.Context, signedTx *types.Transaction) error {
+	// Повторы при сбоях узлов выполняет пул RPC согласно RetryPolicy
+	err := c.client.SendTransaction(ctx, signedTx)
+
+	if errors.Is(err, ErrSendUnconfirmed) {
+		// Nonce занят, возможно, этой же транзакцией — ждем ее по хэшу
+		return fmt.Errorf("%w (tx %s)", err, signedTx.Hash().Hex())
 	}
 
-	for attempt := 0; attempt < client.RetryCount; attempt++ {
-		if err = c.client.SendTransaction(context.Background(), signedTx); err == nil {
-			break
+	if isNonceTooLow(err) {
+		if from, senderErr := txSender(signedTx); senderErr == nil {
+			c.nonces.Resync(from)
 		}
-		log.Printf("[WARN] attempt %d failed: %v", attempt+1, err)
-		time.Sleep(time.Second * 2)
+		return fmt.Errorf("%w (nonce %d): %v", ErrNonceTooLow, signedTx.Nonce(), err)
+	}
+
+	if err != nil && (isEndpointError(err) || ctx.Err() != nil) {
+		// Запрос мог дойти до узла — nonce не возвращаем, иначе его получит следующая транзакция
+		return fmt.Errorf("%w (tx %s): %v", ErrSendUnconfirmed, signedTx.Hash().Hex(), err)
+	}
+
+	if err != nil {
+		c.releaseNonce(signedTx)
+		return fmt.Errorf("failed to send transaction: %v", err)
 	}
 
-	log.Printf("[NONCE: %v] Transaction sent: %s/%s", preparedData.Nonce, client.ExploerTx, signedTx.Hash().Hex())
+	log.Printf("[NONCE: %v] Transaction sent: %s/%s", signedTx.Nonce(), client.ExploerTx, signedTx.Hash().Hex())
 
-	return c.waitForTransactionSuccess(signedTx.Hash(), client.WaitingTimeout)
+	return nil
 }
 
-func (c *EthClient) prepareData(ctx context.Context, amount float32, to string, validatorID uint8, privatekey *ecdsa.PrivateKey) (ChainData, error) {
-	bigAmount, err := utils.ConvertToWei(float64(amount), client.EthDecimal)
+// WaitForTransaction ждет включения транзакции в блок, заменяя ее при зависании (см. ReplacementPolicy),
+// и логирует декодированные события стейкинг-контракта. Возвращает попавшую в блок версию транзакции.
+func (c *EthClient) WaitForTransaction(ctx context.Context, signedTx *types.Transaction, signer Signer, onReplace func(*types.Transaction)) (*types.Transaction, error) {
+	minedTx, receipt, err := c.waitMined(ctx, signedTx, signer, onReplace)
+	if from, senderErr := txSender(minedTx); senderErr == nil {
+		switch {
+		case err == nil || errors.Is(err, ErrTxReverted):
+			// Транзакция в блоке — ее nonce израсходован независимо от результата
+			c.nonces.Confirm(from, minedTx.Nonce())
+		case errors.Is(err, ErrTxTimeout):
+			// Транзакция могла выпасть из mempool — следующий nonce сверяется с сетью
+			c.nonces.Expire(from, minedTx.Nonce())
+		}
+	}
 	if err != nil {
-		return ChainData{}, err
+		return minedTx, err
 	}
 
-	chainID, err := c.client.NetworkID(ctx)
+	if minedTx.To() == nil {
+		return minedTx, nil
+	}
+
+	events, err := DecodeStakingEvents(*minedTx.To(), receipt.Logs)
 	if err != nil {
-		return ChainData{}, fmt.Errorf("failed to get ChainID: %v", err)
+		log.Printf("[WARN] %v", err)
+	}
+
+	for _, event := range events {
+		log.Printf("[EVENT] %s", event)
 	}
 
-	ownerAddr, err := utils.DeriveAddress(privatekey)
+	return minedTx, nil
+}
+
+// TransactionReceiptStatus разово проверяет receipt: mined=false, если транзакция еще не в блоке.
+func (c *EthClient) TransactionReceiptStatus(ctx context.Context, txHash common.Hash) (mined bool, success bool, err error) {
+	receipt, err := c.client.TransactionReceipt(ctx, txHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return false, false, nil
+	}
 	if err != nil {
-		return ChainData{}, err
+		return false, false, fmt.Errorf("failed to get transaction receipt: %w", err)
+	}
+
+	return true, receipt.Status == types.ReceiptStatusSuccessful, nil
+}
+
+// EstimateDelegateFee оценивает максимальную комиссию delegate (gasLimit * maxFeePerGas) от адреса from
+// с запасом на замену зависшей транзакции (см. replacementFeeCap).
+func (c *EthClient) EstimateDelegateFee(ctx context.Context, amount *big.Int, to string, from common.Address, validatorID uint64) (*big.Int, error) {
+	txData, err := c.CreateDelegateData(validatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create delegate data: %v", err)
 	}
 
-	{
-		balance, err := c.BalanceCheck(common.HexToAddress(to))
+	contract := common.HexToAddress(to)
+	var fee *big.Int
+	err = c.whileFeeAboveCap(ctx, func() error {
+		gasLimit, _, maxFeePerGas, err := c.GetGasValues(ctx, ethereum.CallMsg{
+			From:  from,
+			To:    &contract,
+			Value: amount,
+			Data:  txData,
+		})
 		if err != nil {
-			return ChainData{}, err
+			return err
 		}
 
-		if balance.Cmp(bigAmount) == -1 {
-			return ChainData{}, fmt.Errorf("[%s] low balance: %v", ownerAddr, balance)
+		fee = new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), c.replacementFeeCap(maxFeePerGas))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate delegate fee: %w", err)
+	}
+
+	return fee, nil
+}
+
+func (c *EthClient) signStakingTx(ctx context.Context, amount *big.Int, to string, signer Signer, txData []byte) (*types.Transaction, ChainData, error) {
+	var preparedData ChainData
+	err := c.whileFeeAboveCap(ctx, func() (err error) {
+		preparedData, err = c.prepareData(ctx, amount, to, txData, signer)
+		return err
+	})
+	if err != nil {
+		return nil, ChainData{}, fmt.Errorf("failed to prepare data: %w", err)
+	}
+
+	return c.signPrepared(ctx, preparedData, signer)
+}
+
+// signPrepared подписывает подготовленную транзакцию; при ошибке подписи nonce возвращается менеджеру.
+func (c *EthClient) signPrepared(ctx context.Context, preparedData ChainData, signer Signer) (*types.Transaction, ChainData, error) {
+	dynamicTx := types.DynamicFeeTx{
+		ChainID:   preparedData.ChainID,
+		Nonce:     preparedData.Nonce,
+		GasTipCap: preparedData.MaxPriorityFeePerGas,
+		GasFeeCap: preparedData.MaxFeePerGas,
+		Gas:       preparedData.GasLimit,
+		To:        preparedData.DestinationAddr,
+		Value:     preparedData.Amount,
+		Data:      preparedData.TxData,
+	}
+
+	signedTx, err := signer.SignTx(ctx, types.NewTx(&dynamicTx), preparedData.ChainID)
+	if err != nil {
+		c.nonces.Release(signer.Address(), preparedData.Nonce)
+		return nil, ChainData{}, fmt.Errorf("failed to sign transaction: %v", err)
+	}
+
+	return signedTx, preparedData, nil
+}
+
+// whileFeeAboveCap повторяет fn, пока комиссия сети выше потолка из конфига:
+// лучше подождать, чем отправить дорогую транзакцию.
+func (c *EthClient) whileFeeAboveCap(ctx context.Context, fn func() error) error {
+	err := fn()
+	for errors.Is(err, ErrFeeAboveCap) {
+		log.Printf("[WARN] %v, next fee check in %s", err, c.opts.Gas.WaitInterval)
+
+		select {
+		case <-time.After(c.opts.Gas.WaitInterval):
+		case <-ctx.Done():
+			return ctx.Err()
 		}
+
+		err = fn()
 	}
 
-	txData, err := c.CreateDelegateData(validatorID)
+	return err
+}
+
+func (c *EthClient) releaseNonce(signedTx *types.Transaction) {
+	if from, err := txSender(signedTx); err == nil {
+		c.nonces.Release(from, signedTx.Nonce())
+	}
+}
+
+func txSender(signedTx *types.Transaction) (common.Address, error) {
+	return types.Sender(types.LatestSignerForChainID(signedTx.ChainId()), signedTx)
+}
+
+func (c *EthClient) prepareData(ctx context.Context, amount *big.Int, to string, txData []byte, signer Signer) (ChainData, error) {
+	chainID, err := c.client.NetworkID(ctx)
 	if err != nil {
-		return ChainData{}, fmt.Errorf("failed to create delegate data: %v", err)
+		return ChainData{}, fmt.Errorf("failed to get ChainID: %v", err)
 	}
 
+	ownerAddr := signer.Address()
+
 	contract := common.HexToAddress(to)
-	gasLimit, maxPriorityFeePerGas, maxFeePerGas, err := c.GetGasValues(ethereum.CallMsg{
+	gasLimit, maxPriorityFeePerGas, maxFeePerGas, err := c.GetGasValues(ctx, ethereum.CallMsg{
 		From:  ownerAddr,
 		To:    &contract,
-		Value: bigAmount,
+		Value: amount,
 		Data:  txData,
 	})
 	if err != nil {
-		return ChainData{}, fmt.Errorf("failed to estimate gas: %v", err)
+		return ChainData{}, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	// Владелец платит и сумму, и комиссию — проверяем баланс с запасом на максимальную комиссию
+	balance, err := c.BalanceCheck(ctx, ownerAddr)
+	if err != nil {
+		return ChainData{}, err
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), maxFeePerGas)
+	if need := new(big.Int).Add(amount, fee); balance.Cmp(need) < 0 {
+		return ChainData{}, fmt.Errorf("%w: %s has %s MON, needs %s MON (amount %s + max fee %s)", ErrInsufficientBalance, ownerAddr,
+			utils.FormatEther(balance), utils.FormatEther(need),
+			utils.FormatEther(amount), utils.FormatEther(fee))
+	}
+
+	nonce, err := c.nonces.Next(ctx, ownerAddr)
+	if err != nil {
+		return ChainData{}, err
 	}
 
 	return ChainData{
-		Amount:               bigAmount,
+		Amount:               amount,
 		ChainID:              chainID,
 		MaxPriorityFeePerGas: maxPriorityFeePerGas,
 		MaxFeePerGas:         maxFeePerGas,
 		GasLimit:             gasLimit,
-		Nonce:                c.GetNonce(ownerAddr),
+		Nonce:                nonce,
 		TxData:               txData,
 		DestinationAddr:      &contract,
 	}, nil
 }
 
-func (c *EthClient) waitForTransactionSuccess(txHash common.Hash, timeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	ticker := time.NewTicker(3 * time.Second)
-	defer ticker.Stop()
+func (c *EthClient) CreateDelegateData(validatorID uint64) ([]byte, error) {
+	return packStaking("delegate", validatorID)
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("transaction wait timeout")
-		case <-ticker.C:
-			receipt, err := c.client.TransactionReceipt(context.Background(), txHash)
-			if err != nil {
-				log.Printf("error getting transaction receipt: %v", err)
-				continue
-			}
-
-			if receipt.Status == 1 {
-				return nil
-			} else {
-				return fmt.Errorf("transaction failed")
-			}
-		}
+func (c *EthClient) CreateUndelegateData(validatorID uint64, amount *big.Int, withdrawID uint8) ([]byte, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("undelegate amount must be positive")
 	}
+
+	return packStaking("undelegate", validatorID, amount, withdrawID)
 }
 
-func (c *EthClient) CreateDelegateData(validatorID uint8) ([]byte, error) {
-	dataHex := client.DelegateSelector + fmt.Sprintf("%064x", validatorID)
+func (c *EthClient) CreateWithdrawData(validatorID uint64, withdrawID uint8) ([]byte, error) {
+	return packStaking("withdraw", validatorID, withdrawID)
+}
+
+func (c *EthClient) CreateCompoundData(validatorID uint64) ([]byte, error) {
+	return packStaking("compound", validatorID)
+}
+
+func (c *EthClient) CreateClaimRewardsData(validatorID uint64) ([]byte, error) {
+	return packStaking("claimRewards", validatorID)
+}
 
-	data, err := hexutil.Decode("0x" + dataHex)
+func packStaking(method string, args ...interface{}) ([]byte, error) {
+	data, err := client.StakingABI.Pack(method, args...)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка создания данных транзакции: %w", err)
+		return nil, fmt.Errorf("ошибка создания данных транзакции %s: %w", method, err)
 	}
 
 	return data, nil
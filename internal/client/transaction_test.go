@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+var errAny = errors.New("any error")
+
+func TestBroadcastTransactionOutcome(t *testing.T) {
+	down := httpStatus(http.StatusServiceUnavailable)
+
+	tests := []struct {
+		name string
+		// Ответы узла на попытки отправки по порядку
+		responses []error
+		// nil — успешная отправка; errAny — любая ошибка
+		wantErr error
+		// Nonce остается занятым: следующая транзакция получает следующий nonce
+		wantKept bool
+	}{
+		{"accepted", []error{nil}, nil, true},
+		{"already known after timeout", []error{down, nodeError{message: "already known"}}, nil, true},
+		{"nonce too low after timeout", []error{down, nodeError{message: "nonce too low"}}, ErrSendUnconfirmed, true},
+		{"nonce too low on first attempt", []error{nodeError{message: "nonce too low"}}, ErrNonceTooLow, false},
+		{"node unreachable", []error{down, down}, ErrSendUnconfirmed, true},
+		{"rejected by node", []error{nodeError{message: "insufficient funds for gas * price + value"}}, errAny, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := newTestSigner(t)
+
+			node := newFakeNode(t)
+			sends := 0
+			node.handle("eth_sendRawTransaction", func([]json.RawMessage) (any, error) {
+				err := tt.responses[min(sends, len(tt.responses)-1)]
+				sends++
+				return nil, err
+			})
+
+			chain := &fakeChain{nonce: 3}
+			c := &EthClient{
+				client: dialTestPool(t, PoolOptions{Retry: RetryPolicy{MaxAttempts: 2}}, node),
+				nonces: NewNonceManager(chain.fetch),
+			}
+
+			nonce, err := c.nonces.Next(context.Background(), signer.Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+			tx := signTestTx(t, signer, nonce, 100, 1000)
+
+			err = c.BroadcastTransaction(context.Background(), tx)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("BroadcastTransaction: %v", err)
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("BroadcastTransaction succeeded, want a rejection")
+			case tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("BroadcastTransaction error = %v, want %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrSendUnconfirmed) && errors.Is(err, ErrNonceTooLow) {
+				t.Fatalf("unconfirmed send also reported as nonce too low: %v", err)
+			}
+
+			chain.nonce = 3
+			next, err := c.nonces.Next(context.Background(), signer.Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if kept := next == nonce+1; kept != tt.wantKept {
+				t.Errorf("next nonce %d after sending %d: kept %v, want %v", next, nonce, kept, tt.wantKept)
+			}
+		})
+	}
+}
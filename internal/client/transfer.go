@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"ms/pkg/utils"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SignTransfer готовит и подписывает перевод нативных MON на адрес to, не отправляя его.
+// Nonce резервируется в NonceManager, поэтому переводы с одного адреса можно подписывать
+// подряд, не дожидаясь включения предыдущих в блок.
+func (c *EthClient) SignTransfer(ctx context.Context, amount *big.Int, to common.Address, signer Signer) (*types.Transaction, TxPlan, error) {
+	signedTx, preparedData, err := c.signStakingTx(ctx, amount, to.Hex(), signer, nil)
+	if err != nil {
+		return nil, TxPlan{}, err
+	}
+
+	return signedTx, newTxPlan(signedTx, preparedData), nil
+}
+
+// SignSweep подписывает перевод всего баланса signer на адрес to за вычетом максимальной комиссии
+// (gasLimit * maxFeePerGas). Если баланс не покрывает комиссию, возвращается ErrInsufficientBalance.
+func (c *EthClient) SignSweep(ctx context.Context, to common.Address, signer Signer) (*types.Transaction, TxPlan, error) {
+	var preparedData ChainData
+	err := c.whileFeeAboveCap(ctx, func() (err error) {
+		preparedData, err = c.prepareSweep(ctx, to, signer)
+		return err
+	})
+	if err != nil {
+		return nil, TxPlan{}, fmt.Errorf("failed to prepare sweep: %w", err)
+	}
+
+	signedTx, preparedData, err := c.signPrepared(ctx, preparedData, signer)
+	if err != nil {
+		return nil, TxPlan{}, err
+	}
+
+	return signedTx, newTxPlan(signedTx, preparedData), nil
+}
+
+func (c *EthClient) prepareSweep(ctx context.Context, to common.Address, signer Signer) (ChainData, error) {
+	chainID, err := c.client.NetworkID(ctx)
+	if err != nil {
+		return ChainData{}, fmt.Errorf("failed to get ChainID: %v", err)
+	}
+
+	ownerAddr := signer.Address()
+
+	// Газ перевода не зависит от суммы, а оценка с полным балансом упала бы на нехватке средств на комиссию
+	gasLimit, maxPriorityFeePerGas, maxFeePerGas, err := c.GetGasValues(ctx, ethereum.CallMsg{
+		From: ownerAddr,
+		To:   &to,
+	})
+	if err != nil {
+		return ChainData{}, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	balance, err := c.BalanceCheck(ctx, ownerAddr)
+	if err != nil {
+		return ChainData{}, err
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), maxFeePerGas)
+	amount := new(big.Int).Sub(balance, fee)
+	if amount.Sign() <= 0 {
+		return ChainData{}, fmt.Errorf("%w: %s has %s MON, max fee is %s MON", ErrInsufficientBalance, ownerAddr,
+			utils.FormatEther(balance), utils.FormatEther(fee))
+	}
+
+	nonce, err := c.nonces.Next(ctx, ownerAddr)
+	if err != nil {
+		return ChainData{}, err
+	}
+
+	return ChainData{
+		Amount:               amount,
+		ChainID:              chainID,
+		MaxPriorityFeePerGas: maxPriorityFeePerGas,
+		MaxFeePerGas:         maxFeePerGas,
+		GasLimit:             gasLimit,
+		Nonce:                nonce,
+		DestinationAddr:      &to,
+	}, nil
+}
+
+// ReleaseTransaction возвращает nonce подписанной транзакции, которая не будет отправлена.
+// Несколько транзакций одного адреса возвращаются в обратном порядке, чтобы не оставить дыр.
+func (c *EthClient) ReleaseTransaction(signedTx *types.Transaction) {
+	c.releaseNonce(signedTx)
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"ms/pkg/utils"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AccountOverride — настройки отдельного аккаунта поверх общих; незаданные поля берутся из config.yaml.
+type AccountOverride struct {
+	// Адрес аккаунта или метка из файла ключей.
+	Match      string       `yaml:"match"`
+	Stake      *AmountRange `yaml:"stake"`
+	Delay      *Range       `yaml:"delay"`
+	Validators []uint64     `yaml:"validators"`
+	Enabled    *bool        `yaml:"enabled"`
+}
+
+// Колонки CSV файла аккаунтов; пустая ячейка — значение не переопределяется.
+var accountsCSVHeader = []string{"match", "stake_min", "stake_max", "validators", "delay_min", "delay_max", "enabled"}
+
+// LoadAccountOverrides загружает настройки аккаунтов из YAML (список под ключом accounts) или CSV файла.
+func LoadAccountOverrides(path string) ([]AccountOverride, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла аккаунтов: %w", err)
+	}
+	defer file.Close()
+
+	var overrides []AccountOverride
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		overrides, err = parseAccountsCSV(file)
+	case ".yaml", ".yml":
+		var doc struct {
+			Accounts []AccountOverride `yaml:"accounts"`
+		}
+		err = yaml.NewDecoder(file).Decode(&doc)
+		if err == io.EOF {
+			err = nil
+		}
+		overrides = doc.Accounts
+	default:
+		return nil, fmt.Errorf("файл аккаунтов должен быть .yaml, .yml или .csv: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора файла аккаунтов: %w", err)
+	}
+
+	if err := validateOverrides(overrides); err != nil {
+		return nil, fmt.Errorf("ошибка валидации файла аккаунтов: %w", err)
+	}
+
+	return overrides, nil
+}
+
+func parseAccountsCSV(r io.Reader) ([]AccountOverride, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = len(accountsCSVHeader)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range accountsCSVHeader {
+		if strings.TrimSpace(strings.ToLower(header[i])) != name {
+			return nil, fmt.Errorf("ожидается заголовок %s", strings.Join(accountsCSVHeader, ","))
+		}
+	}
+
+	var overrides []AccountOverride
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return overrides, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		o, err := parseAccountsRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: %w", line, err)
+		}
+
+		overrides = append(overrides, o)
+	}
+}
+
+func parseAccountsRecord(record []string) (AccountOverride, error) {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+
+	o := AccountOverride{Match: record[0]}
+
+	var err error
+	if o.Stake, err = parseCSVAmountRange(record[1], record[2]); err != nil {
+		return o, fmt.Errorf("stake: %w", err)
+	}
+	if o.Delay, err = parseCSVRange(record[4], record[5]); err != nil {
+		return o, fmt.Errorf("delay: %w", err)
+	}
+
+	// Валидаторы перечисляются через пробел или ';', чтобы не конфликтовать с разделителем CSV
+	for _, field := range strings.FieldsFunc(record[3], func(r rune) bool { return r == ';' || r == ' ' }) {
+		id, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return o, fmt.Errorf("некорректный ID валидатора %q", field)
+		}
+		o.Validators = append(o.Validators, id)
+	}
+
+	if record[6] != "" {
+		enabled, err := strconv.ParseBool(record[6])
+		if err != nil {
+			return o, fmt.Errorf("некорректное значение enabled %q", record[6])
+		}
+		o.Enabled = &enabled
+	}
+
+	return o, nil
+}
+
+func parseCSVRange(minField, maxField string) (*Range, error) {
+	if minField == "" && maxField == "" {
+		return nil, nil
+	}
+	if minField == "" || maxField == "" {
+		return nil, fmt.Errorf("нужно указать и min, и max")
+	}
+
+	min, err := strconv.ParseFloat(minField, 32)
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение %q", minField)
+	}
+	max, err := strconv.ParseFloat(maxField, 32)
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение %q", maxField)
+	}
+
+	return &Range{Min: float32(min), Max: float32(max)}, nil
+}
+
+func parseCSVAmountRange(minField, maxField string) (*AmountRange, error) {
+	if minField == "" && maxField == "" {
+		return nil, nil
+	}
+	if minField == "" || maxField == "" {
+		return nil, fmt.Errorf("нужно указать и min, и max")
+	}
+
+	min, err := utils.ParseAmount(minField)
+	if err != nil {
+		return nil, err
+	}
+	max, err := utils.ParseAmount(maxField)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AmountRange{Min: min, Max: max}, nil
+}
+
+func validateOverrides(overrides []AccountOverride) error {
+	seen := make(map[string]struct{}, len(overrides))
+	for i, o := range overrides {
+		if o.Match == "" {
+			return fmt.Errorf("аккаунт #%d: не задан match (адрес или метка)", i+1)
+		}
+
+		key := strings.ToLower(o.Match)
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("аккаунт %s указан несколько раз", o.Match)
+		}
+		seen[key] = struct{}{}
+
+		if o.Stake != nil && (o.Stake.Min.Sign() < 0 || o.Stake.Max.Cmp(o.Stake.Min) < 0) {
+			return fmt.Errorf("аккаунт %s: некорректный диапазон stake", o.Match)
+		}
+		if o.Delay != nil && (o.Delay.Min < 0 || o.Delay.Max < o.Delay.Min) {
+			return fmt.Errorf("аккаунт %s: некорректный диапазон delay", o.Match)
+		}
+	}
+
+	return nil
+}
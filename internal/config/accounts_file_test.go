@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeAccountsFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadAccountOverridesCSV(t *testing.T) {
+	path := writeAccountsFile(t, "accounts.csv", `match,stake_min,stake_max,validators,delay_min,delay_max,enabled
+# комментарии пропускаются
+0x70997970C51812dc3A010C7d01b50e0d17dc79C8, 1.5, 2.25, 1;7 12, 0.5, 2.75, true
+alice-3,,,,,,false
+bob,,,,,,
+`)
+
+	overrides, err := LoadAccountOverrides(path)
+	if err != nil {
+		t.Fatalf("LoadAccountOverrides: %v", err)
+	}
+	if len(overrides) != 3 {
+		t.Fatalf("got %d overrides, want 3", len(overrides))
+	}
+
+	o := overrides[0]
+	if o.Stake == nil || o.Stake.Min.String() != "1.5" || o.Stake.Max.String() != "2.25" {
+		t.Errorf("stake = %+v, want 1.5..2.25", o.Stake)
+	}
+	// Дробная задержка не округляется
+	if o.Delay == nil || o.Delay.Min != 0.5 || o.Delay.Max != 2.75 {
+		t.Errorf("delay = %+v, want 0.5..2.75", o.Delay)
+	}
+	if len(o.Validators) != 3 || o.Validators[0] != 1 || o.Validators[1] != 7 || o.Validators[2] != 12 {
+		t.Errorf("validators = %v, want [1 7 12]", o.Validators)
+	}
+	if o.Enabled == nil || !*o.Enabled {
+		t.Errorf("enabled = %v, want true", o.Enabled)
+	}
+
+	if o := overrides[1]; o.Match != "alice-3" || o.Enabled == nil || *o.Enabled {
+		t.Errorf("override %+v, want alice-3 disabled", o)
+	}
+
+	// Пустые ячейки не переопределяют общие настройки
+	if o := overrides[2]; o.Stake != nil || o.Delay != nil || o.Validators != nil || o.Enabled != nil {
+		t.Errorf("override %+v, want only match", o)
+	}
+}
+
+func TestLoadAccountOverridesYAML(t *testing.T) {
+	path := writeAccountsFile(t, "accounts.yaml", `accounts:
+  - match: alice-3
+    stake: {min: "0.1", max: "0.3"}
+    delay: {min: 1.5, max: 4}
+    validators: [3, 5]
+  - match: bob
+    enabled: false
+`)
+
+	overrides, err := LoadAccountOverrides(path)
+	if err != nil {
+		t.Fatalf("LoadAccountOverrides: %v", err)
+	}
+	if len(overrides) != 2 {
+		t.Fatalf("got %d overrides, want 2", len(overrides))
+	}
+
+	o := overrides[0]
+	if o.Stake == nil || o.Stake.Min.String() != "0.1" || o.Stake.Max.String() != "0.3" {
+		t.Errorf("stake = %+v, want 0.1..0.3", o.Stake)
+	}
+	if o.Delay == nil || o.Delay.Min != 1.5 || o.Delay.Max != 4 {
+		t.Errorf("delay = %+v, want 1.5..4", o.Delay)
+	}
+	if len(o.Validators) != 2 || o.Enabled != nil {
+		t.Errorf("override %+v, want validators [3 5] and no enabled", o)
+	}
+	if o := overrides[1]; o.Enabled == nil || *o.Enabled {
+		t.Errorf("override %+v, want bob disabled", o)
+	}
+
+	// Пустой файл — нет переопределений
+	if overrides, err := LoadAccountOverrides(writeAccountsFile(t, "empty.yml", "")); err != nil || len(overrides) != 0 {
+		t.Errorf("empty file = %v, %v; want no overrides", overrides, err)
+	}
+}
+
+func TestLoadAccountOverridesErrors(t *testing.T) {
+	const header = "match,stake_min,stake_max,validators,delay_min,delay_max,enabled\n"
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{"unknown extension", "accounts.txt", "", ".yaml, .yml или .csv"},
+		{"wrong header", "a.csv", "address,stake_min,stake_max,validators,delay_min,delay_max,enabled\n", "ожидается заголовок"},
+		{"missing column", "a.csv", header + "alice,1,2,,1,2\n", "wrong number of fields"},
+		{"half stake range", "a.csv", header + "alice,1,,,,,\n", "строка 2: stake"},
+		{"bad amount", "a.csv", header + "alice,1,two,,,,\n", "строка 2: stake"},
+		{"bad delay", "a.csv", header + "alice,,,,1,soon,\n", "строка 2: delay"},
+		{"bad validator", "a.csv", header + "alice,,,1;x,,,\n", `ID валидатора "x"`},
+		{"bad enabled", "a.csv", header + "alice,,,,,,maybe\n", "enabled"},
+		{"empty match", "a.csv", header + ",1,2,,,,\n", "не задан match"},
+		{"duplicate address", "a.csv", header + "0xAbC0000000000000000000000000000000000001,,,,,,\n0xabc0000000000000000000000000000000000001,,,,,,false\n", "несколько раз"},
+		{"reversed stake", "a.yaml", "accounts:\n  - match: alice\n    stake: {min: \"2\", max: \"1\"}\n", "диапазон stake"},
+		{"negative delay", "a.yaml", "accounts:\n  - match: alice\n    delay: {min: -1, max: 1}\n", "диапазон delay"},
+		{"malformed yaml", "a.yaml", "accounts: [\n", "ошибка разбора"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadAccountOverrides(writeAccountsFile(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadAccountOverrides error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,17 +1,227 @@
 package config
 
+import "ms/pkg/utils"
+
+const (
+	KeysModeStrict  = "strict"
+	KeysModeLenient = "lenient"
+)
+
+// Стратегии выбора суммы стейка.
+const (
+	StakeStrategyFixed         = "fixed"
+	StakeStrategyRange         = "range"
+	StakeStrategyPercent       = "percent"
+	StakeStrategyAllButReserve = "allButReserve"
+)
+
+// Стратегии выбора валидатора.
+const (
+	SelectionRandom           = "random"
+	SelectionRoundRobin       = "roundRobin"
+	SelectionWeighted         = "weighted"
+	SelectionLowestCommission = "lowestCommission"
+	SelectionLeastDelegated   = "leastDelegated"
+	SelectionStakeWeighted    = "stakeWeighted"
+)
+
 type (
 	AppConfig struct {
-		Stake           Range   `yaml:"stake"`
-		Delay           Range   `yaml:"delay"`
-		Validators      []uint8 `yaml:"validators"`
-		ContractAddress string  `yaml:"contractAddress"`
-		PrivateKeysFile string  `yaml:"privateKeysFile"`
-		RPCString       string  `yaml:"rpc"`
+		Stake      StakeConfig      `yaml:"stake"`
+		Delay      Range            `yaml:"delay"`
+		Validators ValidatorsConfig `yaml:"validators"`
+		// Кэш обнаруженных валидаторов для validators: auto или фильтра.
+		ValidatorDiscovery ValidatorDiscoveryConfig `yaml:"validatorDiscovery"`
+		// Как выбирать валидатора из validators для каждого аккаунта.
+		ValidatorSelection ValidatorSelectionConfig `yaml:"validatorSelection"`
+		ContractAddress    string                   `yaml:"contractAddress"`
+		PrivateKeysFile    string                   `yaml:"privateKeysFile"`
+		// Файл с настройками отдельных аккаунтов (YAML или CSV); пусто — общие настройки для всех.
+		AccountsFile string     `yaml:"accountsFile"`
+		RPCString    string     `yaml:"rpc"`
+		RPCs         []RPC      `yaml:"rpcs"`
+		RPCPool      RPCPool    `yaml:"rpcPool"`
+		JournalFile  string     `yaml:"journalFile"`
+		Keys         KeysConfig `yaml:"keys"`
+
+		Unstake     UnstakeConfig     `yaml:"unstake"`
+		Fund        FundConfig        `yaml:"fund"`
+		Sweep       SweepConfig       `yaml:"sweep"`
+		Gas         GasConfig         `yaml:"gas"`
+		Replacement ReplacementConfig `yaml:"replacement"`
+	}
+
+	// StakeConfig — сумма стейка. Для range min/max задают диапазон случайной суммы, для остальных
+	// стратегий min — минимальная сумма (аккаунт пропускается, если не может ее внести), max — потолок (0 — без потолка).
+	StakeConfig struct {
+		AmountRange `yaml:",inline"`
+		// fixed, range, percent или allButReserve.
+		Strategy string `yaml:"strategy"`
+		// Сумма для fixed, MON.
+		Amount utils.Amount `yaml:"amount"`
+		// Процент свободного баланса (баланс минус комиссия) для percent.
+		Percent float32 `yaml:"percent"`
+		// Остаток на аккаунте для allButReserve, MON.
+		Reserve utils.Amount `yaml:"reserve"`
+		// Шаг случайной суммы для range, MON: при 0.01 суммы получаются вида 3.27.
+		Granularity utils.Amount `yaml:"granularity"`
+	}
+
+	// KeysConfig — зашифрованные источники ключей вместо открытого privateKeysFile.
+	KeysConfig struct {
+		// Каталог с V3 keystore файлами.
+		KeystoreDir string `yaml:"keystoreDir"`
+		// Зашифрованный файл ключей (создается командой keys encrypt).
+		EncryptedFile string `yaml:"encryptedFile"`
+		// Переменная окружения с паролем; если она пуста, пароль спрашивается в терминале.
+		PassphraseEnv string `yaml:"passphraseEnv"`
+		// strict — остановка при любом некорректном или повторяющемся ключе, lenient — такие ключи пропускаются.
+		Mode string `yaml:"mode"`
+
+		HD           HDConfig           `yaml:"hd"`
+		RemoteSigner RemoteSignerConfig `yaml:"remoteSigner"`
+	}
+
+	// RemoteSignerConfig — внешний сервис подписи (Clef, web3signer); ключи не попадают в процесс бота.
+	RemoteSignerConfig struct {
+		// JSON-RPC адрес сервиса подписи.
+		URL string `yaml:"url"`
+		// Адреса аккаунтов; пустой список — все адреса сервиса (eth_accounts / account_list).
+		Addresses []string `yaml:"addresses"`
+	}
+
+	// HDConfig — вывод аккаунтов из BIP-39 мнемоники по BIP-32/44.
+	HDConfig struct {
+		// Переменная окружения с мнемоникой.
+		MnemonicEnv string `yaml:"mnemonicEnv"`
+		// Зашифрованный файл с мнемоникой (keys encrypt --mnemonic), пароль — из passphraseEnv.
+		MnemonicFile string `yaml:"mnemonicFile"`
+		// Шаблон пути деривации с {index}.
+		Path string `yaml:"path"`
+		// Диапазон индексов аккаунтов, включительно.
+		From uint32 `yaml:"from"`
+		To   uint32 `yaml:"to"`
+	}
+
+	// RPC — дополнительный RPC узел; меньший priority — более предпочтительный узел.
+	RPC struct {
+		URL      string `yaml:"url"`
+		Priority int    `yaml:"priority"`
+	}
+
+	// RPCPool — проверка узлов, кворум чтений и рассылка транзакций.
+	RPCPool struct {
+		// Интервал проверки высоты блока на узлах (секунды); 0 — без фоновой проверки.
+		HealthCheckInterval float32 `yaml:"healthCheckInterval"`
+		// Допустимое отставание узла от лучшего в блоках.
+		MaxBlockLag uint64 `yaml:"maxBlockLag"`
+		// Сколько узлов должны совпасть для чтения баланса и receipt.
+		ReadQuorum int `yaml:"readQuorum"`
+		// Отправлять подписанную транзакцию на все узлы.
+		BroadcastAll bool `yaml:"broadcastAll"`
+		// Лимит запросов в секунду на каждый узел; 0 — без лимита.
+		RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+		// Допустимая пачка запросов сверх лимита.
+		Burst int `yaml:"burst"`
+		// Таймаут одного RPC запроса к узлу (секунды).
+		CallTimeout float32 `yaml:"callTimeout"`
+		// Таймаут отправки транзакции на узел (секунды).
+		SendTimeout float32 `yaml:"sendTimeout"`
+
+		Retry RetryConfig `yaml:"retry"`
+	}
+
+	// RetryConfig — повторы RPC вызовов при временных сбоях (лимит, таймаут, обрыв соединения).
+	RetryConfig struct {
+		MaxAttempts int `yaml:"maxAttempts"`
+		// Базовая и максимальная задержка экспоненциального backoff (секунды).
+		BaseDelay float32 `yaml:"baseDelay"`
+		MaxDelay  float32 `yaml:"maxDelay"`
+	}
+
+	// GasConfig — политика комиссий и лимита газа.
+	GasConfig struct {
+		// maxFeePerGas = baseFee * baseFeeMultiplier + tip.
+		BaseFeeMultiplier float64 `yaml:"baseFeeMultiplier"`
+		// Фиксированный tip, gwei; 0 — брать из сети.
+		Tip utils.Gwei `yaml:"tip"`
+		// Перцентиль наград eth_feeHistory для tip; 0 — eth_maxPriorityFeePerGas.
+		TipPercentile float64 `yaml:"tipPercentile"`
+		// Количество блоков для eth_feeHistory.
+		FeeHistoryBlocks uint64 `yaml:"feeHistoryBlocks"`
+		// Запас к eth_estimateGas, проценты.
+		GasLimitBuffer uint64 `yaml:"gasLimitBuffer"`
+		// Потолок maxFeePerGas, gwei; выше него отправка откладывается. 0 — без потолка.
+		MaxFeePerGas utils.Gwei `yaml:"maxFeePerGas"`
+		// Пауза между проверками комиссии, пока она выше потолка (секунды).
+		WaitInterval float32 `yaml:"waitInterval"`
+	}
+
+	// ReplacementConfig — замена зависших транзакций с поднятием комиссий.
+	ReplacementConfig struct {
+		// Через сколько блоков без включения транзакция заменяется; 0 — не заменять.
+		StuckBlocks uint64 `yaml:"stuckBlocks"`
+		// Прирост GasTipCap/GasFeeCap за одну замену, проценты (не меньше 10).
+		BumpPercent uint64 `yaml:"bumpPercent"`
+		// Потолок maxFeePerGas для замен, gwei; 0 — без ограничения.
+		MaxFeePerGas utils.Gwei `yaml:"maxFeePerGas"`
+	}
+
+	UnstakeConfig struct {
+		// Интервал между проверками эпохи (секунды) в ожидании withdraw.
+		PollInterval float32 `yaml:"pollInterval"`
+	}
+
+	ValidatorDiscoveryConfig struct {
+		// Файл кэша набора валидаторов.
+		CacheFile string `yaml:"cacheFile"`
+		// Время жизни кэша (секунды).
+		TTL float32 `yaml:"ttl"`
+	}
+
+	ValidatorSelectionConfig struct {
+		// random, roundRobin, weighted, lowestCommission, leastDelegated или stakeWeighted.
+		Strategy string `yaml:"strategy"`
+		// Веса для weighted: ID валидатора -> вес; валидатор без веса имеет вес 1.
+		Weights map[uint64]uint64 `yaml:"weights"`
+	}
+
+	// FundConfig — пополнение аккаунтов с казначейского адреса (команда fund).
+	FundConfig struct {
+		// Переменная окружения с приватным ключом казначейства.
+		TreasuryKeyEnv string `yaml:"treasuryKeyEnv"`
+		// V3 keystore файл казначейства вместо ключа в окружении; пароль — из keys.passphraseEnv или терминала.
+		TreasuryKeystore string `yaml:"treasuryKeystore"`
+		// Баланс, до которого пополняется каждый аккаунт, MON.
+		TargetBalance utils.Amount `yaml:"targetBalance"`
+		// Фиксированная сумма каждому аккаунту вместо targetBalance, MON.
+		Amount utils.Amount `yaml:"amount"`
+		// Предел расхода казначейства за запуск (переводы плюс максимальные комиссии), MON.
+		SpendCap utils.Amount `yaml:"spendCap"`
+		// Сколько переводов одновременно ждут включения в блок.
+		MaxInFlight int `yaml:"maxInFlight"`
+	}
+
+	// SweepConfig — сбор свободных балансов на казначейский адрес (команда sweep).
+	SweepConfig struct {
+		// Адрес казначейства, куда переводятся балансы.
+		Treasury string `yaml:"treasury"`
+		// Минимальная сумма перевода, MON: меньшие остатки не стоят комиссии.
+		MinAmount utils.Amount `yaml:"minAmount"`
+		// Адреса или метки аккаунтов, которые не нужно трогать.
+		Exclude []string `yaml:"exclude"`
+		// Задержка между аккаунтами, секунды; переводы на казначейство не нуждаются в задержке стейкинга.
+		Delay *Range `yaml:"delay"`
 	}
 
 	Range struct {
 		Min float32 `yaml:"min"`
 		Max float32 `yaml:"max"`
 	}
+
+	// AmountRange — диапазон сумм в MON, значения разбираются точно (без float).
+	AmountRange struct {
+		Min utils.Amount `yaml:"min"`
+		Max utils.Amount `yaml:"max"`
+	}
 )
@@ -2,11 +2,39 @@ package config
 
 import (
 	"fmt"
+	"ms/internal/keys"
+	"ms/pkg/utils"
 	"os"
 
+	"github.com/ethereum/go-ethereum/common"
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	defaultUnstakePollInterval = 300
+	defaultJournalFile         = "run_journal.jsonl"
+	defaultPassphraseEnv       = "MONAD_KEYS_PASSPHRASE"
+	defaultBaseFeeMultiplier   = 1
+	defaultFeeHistoryBlocks    = 20
+	defaultGasWaitInterval     = 30
+	defaultHealthCheckInterval = 30
+	defaultMaxBlockLag         = 5
+	defaultRetryMaxAttempts    = 5
+	defaultRetryBaseDelay      = 0.5
+	defaultRetryMaxDelay       = 10
+	defaultCallTimeout         = 15
+	defaultSendTimeout         = 30
+	defaultTreasuryKeyEnv      = "MONAD_TREASURY_KEY"
+	defaultFundMaxInFlight     = 16
+	defaultValidatorCacheFile  = "validators_cache.json"
+	defaultValidatorCacheTTL   = 3600
+)
+
+var (
+	defaultStakeGranularity = utils.MustParseAmount("0.0001")
+	defaultSweepDelay       = Range{Min: 1, Max: 3}
+)
+
 // LoadConfig загружает конфигурацию из YAML файла
 func LoadConfig(configPath string) (*AppConfig, error) {
 	data, err := os.ReadFile(configPath)
@@ -20,6 +48,8 @@ func LoadConfig(configPath string) (*AppConfig, error) {
 		return nil, fmt.Errorf("ошибка парсинга YAML: %w", err)
 	}
 
+	applyDefaults(&config)
+
 	if err := validateConfig(&config); err != nil {
 		return nil, fmt.Errorf("ошибка валидации конфигурации: %w", err)
 	}
@@ -27,13 +57,91 @@ func LoadConfig(configPath string) (*AppConfig, error) {
 	return &config, nil
 }
 
+// applyDefaults заполняет необязательные поля значениями по умолчанию
+func applyDefaults(config *AppConfig) {
+	if config.Stake.Strategy == "" {
+		config.Stake.Strategy = StakeStrategyRange
+	}
+	if config.ValidatorDiscovery.CacheFile == "" {
+		config.ValidatorDiscovery.CacheFile = defaultValidatorCacheFile
+	}
+	if config.ValidatorDiscovery.TTL == 0 {
+		config.ValidatorDiscovery.TTL = defaultValidatorCacheTTL
+	}
+	if config.ValidatorSelection.Strategy == "" {
+		config.ValidatorSelection.Strategy = SelectionRandom
+	}
+	if config.Stake.Granularity.Sign() == 0 {
+		config.Stake.Granularity = defaultStakeGranularity
+	}
+
+	if config.Unstake.PollInterval == 0 {
+		config.Unstake.PollInterval = defaultUnstakePollInterval
+	}
+
+	if config.Fund.TreasuryKeyEnv == "" {
+		config.Fund.TreasuryKeyEnv = defaultTreasuryKeyEnv
+	}
+	if config.Fund.MaxInFlight == 0 {
+		config.Fund.MaxInFlight = defaultFundMaxInFlight
+	}
+
+	if config.Sweep.Delay == nil {
+		delay := defaultSweepDelay
+		config.Sweep.Delay = &delay
+	}
+
+	if config.JournalFile == "" {
+		config.JournalFile = defaultJournalFile
+	}
+
+	if config.Keys.Mode == "" {
+		config.Keys.Mode = KeysModeStrict
+	}
+	if config.Keys.PassphraseEnv == "" {
+		config.Keys.PassphraseEnv = defaultPassphraseEnv
+	}
+	if config.Keys.HD.Path == "" {
+		config.Keys.HD.Path = keys.DefaultPathTemplate
+	}
+
+	if config.RPCPool.HealthCheckInterval == 0 {
+		config.RPCPool.HealthCheckInterval = defaultHealthCheckInterval
+	}
+	if config.RPCPool.MaxBlockLag == 0 {
+		config.RPCPool.MaxBlockLag = defaultMaxBlockLag
+	}
+	if config.RPCPool.CallTimeout == 0 {
+		config.RPCPool.CallTimeout = defaultCallTimeout
+	}
+	if config.RPCPool.SendTimeout == 0 {
+		config.RPCPool.SendTimeout = defaultSendTimeout
+	}
+	if config.RPCPool.Retry.MaxAttempts == 0 {
+		config.RPCPool.Retry.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if config.RPCPool.Retry.BaseDelay == 0 {
+		config.RPCPool.Retry.BaseDelay = defaultRetryBaseDelay
+	}
+	if config.RPCPool.Retry.MaxDelay == 0 {
+		config.RPCPool.Retry.MaxDelay = defaultRetryMaxDelay
+	}
+
+	if config.Gas.BaseFeeMultiplier == 0 {
+		config.Gas.BaseFeeMultiplier = defaultBaseFeeMultiplier
+	}
+	if config.Gas.FeeHistoryBlocks == 0 {
+		config.Gas.FeeHistoryBlocks = defaultFeeHistoryBlocks
+	}
+	if config.Gas.WaitInterval == 0 {
+		config.Gas.WaitInterval = defaultGasWaitInterval
+	}
+}
+
 // validateConfig проверяет корректность конфигурации
 func validateConfig(config *AppConfig) error {
-	if config.Stake.Min < 0 {
-		return fmt.Errorf("минимальное значение stake не может быть отрицательным")
-	}
-	if config.Stake.Max <= config.Stake.Min {
-		return fmt.Errorf("максимальное значение stake должно быть больше минимального")
+	if err := validateStake(config.Stake); err != nil {
+		return err
 	}
 
 	if config.Delay.Min < 0 {
@@ -43,17 +151,166 @@ func validateConfig(config *AppConfig) error {
 		return fmt.Errorf("максимальное значение delay должно быть больше минимального")
 	}
 
-	if len(config.Validators) == 0 {
-		return fmt.Errorf("список валидаторов не может быть пустым")
+	if !config.Validators.Auto && len(config.Validators.IDs) == 0 {
+		return fmt.Errorf("список валидаторов не может быть пустым (или укажите validators: %s)", ValidatorsAuto)
+	}
+	if filter := config.Validators.Filter; filter.MinStake.Sign() < 0 || (filter.MaxCommission != nil && *filter.MaxCommission < 0) {
+		return fmt.Errorf("minStake и maxCommission фильтра валидаторов не могут быть отрицательными")
+	}
+	if config.ValidatorDiscovery.TTL < 0 {
+		return fmt.Errorf("validatorDiscovery.ttl не может быть отрицательным")
+	}
+
+	switch config.ValidatorSelection.Strategy {
+	case SelectionRandom, SelectionRoundRobin, SelectionWeighted, SelectionLowestCommission, SelectionLeastDelegated, SelectionStakeWeighted:
+	default:
+		return fmt.Errorf("validatorSelection.strategy должен быть %s, %s, %s, %s, %s или %s",
+			SelectionRandom, SelectionRoundRobin, SelectionWeighted, SelectionLowestCommission, SelectionLeastDelegated, SelectionStakeWeighted)
+	}
+
+	if config.Keys.Mode != KeysModeStrict && config.Keys.Mode != KeysModeLenient {
+		return fmt.Errorf("keys.mode должен быть %s или %s", KeysModeStrict, KeysModeLenient)
 	}
 
-	if config.PrivateKeysFile == "" {
+	keySources := 0
+	for _, source := range []string{config.Keys.KeystoreDir, config.Keys.EncryptedFile, config.Keys.HD.MnemonicEnv + config.Keys.HD.MnemonicFile, config.Keys.RemoteSigner.URL} {
+		if source != "" {
+			keySources++
+		}
+	}
+	if keySources > 1 {
+		return fmt.Errorf("keystoreDir, encryptedFile, hd и remoteSigner нельзя задавать одновременно")
+	}
+	for _, addr := range config.Keys.RemoteSigner.Addresses {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("некорректный адрес remoteSigner: %s", addr)
+		}
+	}
+	if config.Keys.HD.MnemonicEnv != "" && config.Keys.HD.MnemonicFile != "" {
+		return fmt.Errorf("мнемоника задается либо mnemonicEnv, либо mnemonicFile")
+	}
+	if config.Keys.HD.To < config.Keys.HD.From {
+		return fmt.Errorf("некорректный диапазон индексов hd: %d..%d", config.Keys.HD.From, config.Keys.HD.To)
+	}
+	if _, err := keys.DerivationPath(config.Keys.HD.Path, config.Keys.HD.From); err != nil {
+		return err
+	}
+	if config.PrivateKeysFile == "" && keySources == 0 {
 		return fmt.Errorf("путь к файлу с приватными ключами не может быть пустым")
 	}
 
-	if config.RPCString == "" {
+	if config.RPCString == "" && len(config.RPCs) == 0 {
 		return fmt.Errorf("RPC строка не может быть пустой")
 	}
+	for i, rpc := range config.RPCs {
+		if rpc.URL == "" {
+			return fmt.Errorf("URL RPC узла #%d не может быть пустым", i+1)
+		}
+	}
+	if config.RPCPool.ReadQuorum > len(config.Endpoints()) {
+		return fmt.Errorf("кворум чтения больше количества RPC узлов")
+	}
+	if config.RPCPool.HealthCheckInterval < 0 {
+		return fmt.Errorf("интервал проверки RPC узлов не может быть отрицательным")
+	}
+	if config.RPCPool.RequestsPerSecond < 0 || config.RPCPool.Burst < 0 {
+		return fmt.Errorf("лимит запросов к RPC не может быть отрицательным")
+	}
+	if config.RPCPool.CallTimeout < 0 || config.RPCPool.SendTimeout < 0 {
+		return fmt.Errorf("таймауты RPC не могут быть отрицательными")
+	}
+	if config.RPCPool.Retry.MaxAttempts < 0 || config.RPCPool.Retry.BaseDelay < 0 || config.RPCPool.Retry.MaxDelay < config.RPCPool.Retry.BaseDelay {
+		return fmt.Errorf("некорректные параметры повторов RPC")
+	}
+
+	if config.Gas.BaseFeeMultiplier < 1 {
+		return fmt.Errorf("множитель base fee должен быть не меньше 1")
+	}
+	if config.Gas.Tip.Sign() < 0 || config.Gas.MaxFeePerGas.Sign() < 0 || config.Gas.WaitInterval < 0 {
+		return fmt.Errorf("параметры газа не могут быть отрицательными")
+	}
+	if config.Gas.TipPercentile < 0 || config.Gas.TipPercentile > 100 {
+		return fmt.Errorf("перцентиль tip должен быть в диапазоне 0..100")
+	}
+
+	if config.Replacement.StuckBlocks > 0 && config.Replacement.BumpPercent < 10 {
+		return fmt.Errorf("повышение комиссии при замене транзакции должно быть не меньше 10%%")
+	}
+	if config.Replacement.MaxFeePerGas.Sign() < 0 {
+		return fmt.Errorf("потолок maxFeePerGas не может быть отрицательным")
+	}
+
+	if config.Unstake.PollInterval < 0 {
+		return fmt.Errorf("интервал проверки эпохи не может быть отрицательным")
+	}
+
+	if config.Fund.TargetBalance.Sign() < 0 || config.Fund.Amount.Sign() < 0 || config.Fund.SpendCap.Sign() < 0 {
+		return fmt.Errorf("суммы fund не могут быть отрицательными")
+	}
+	if config.Fund.TargetBalance.Sign() > 0 && config.Fund.Amount.Sign() > 0 {
+		return fmt.Errorf("fund.targetBalance и fund.amount нельзя задавать одновременно")
+	}
+	if config.Fund.MaxInFlight < 0 {
+		return fmt.Errorf("fund.maxInFlight не может быть отрицательным")
+	}
+
+	if config.Sweep.Treasury != "" && !common.IsHexAddress(config.Sweep.Treasury) {
+		return fmt.Errorf("некорректный адрес sweep.treasury: %s", config.Sweep.Treasury)
+	}
+	if config.Sweep.MinAmount.Sign() < 0 {
+		return fmt.Errorf("sweep.minAmount не может быть отрицательным")
+	}
+	if config.Sweep.Delay.Min < 0 || config.Sweep.Delay.Max < config.Sweep.Delay.Min {
+		return fmt.Errorf("некорректный диапазон sweep.delay")
+	}
+
+	return nil
+}
+
+// Endpoints возвращает все RPC узлы: rpc (наивысший приоритет) и список rpcs.
+func (c *AppConfig) Endpoints() []RPC {
+	var endpoints []RPC
+	if c.RPCString != "" {
+		endpoints = append(endpoints, RPC{URL: c.RPCString, Priority: 0})
+	}
+
+	return append(endpoints, c.RPCs...)
+}
+
+func validateStake(stake StakeConfig) error {
+	if stake.Min.Sign() < 0 {
+		return fmt.Errorf("минимальное значение stake не может быть отрицательным")
+	}
+	if stake.Granularity.Sign() < 0 {
+		return fmt.Errorf("stake.granularity должен быть больше нуля")
+	}
+
+	switch stake.Strategy {
+	case StakeStrategyRange:
+		if stake.Max.Cmp(stake.Min) <= 0 {
+			return fmt.Errorf("максимальное значение stake должно быть больше минимального")
+		}
+		return nil
+	case StakeStrategyFixed:
+		if stake.Amount.Sign() <= 0 || stake.Amount.Cmp(stake.Min) < 0 {
+			return fmt.Errorf("stake.amount должен быть больше нуля и не меньше stake.min")
+		}
+	case StakeStrategyPercent:
+		if stake.Percent <= 0 || stake.Percent > 100 {
+			return fmt.Errorf("stake.percent должен быть в диапазоне (0, 100]")
+		}
+	case StakeStrategyAllButReserve:
+		if stake.Reserve.Sign() < 0 {
+			return fmt.Errorf("stake.reserve не может быть отрицательным")
+		}
+	default:
+		return fmt.Errorf("stake.strategy должен быть %s, %s, %s или %s",
+			StakeStrategyFixed, StakeStrategyRange, StakeStrategyPercent, StakeStrategyAllButReserve)
+	}
+
+	if stake.Max.Sign() != 0 && stake.Max.Cmp(stake.Min) < 0 {
+		return fmt.Errorf("максимальное значение stake должно быть не меньше минимального")
+	}
 
 	return nil
 }
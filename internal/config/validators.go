@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"ms/pkg/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidatorsAuto — значение validators для всех активных валидаторов стейкинг-контракта.
+const ValidatorsAuto = "auto"
+
+// ValidatorsConfig — поле validators: явный список ID, auto (все активные валидаторы)
+// или фильтр по активным валидаторам (minStake, maxCommission, exclude).
+type ValidatorsConfig struct {
+	IDs    []uint64
+	Auto   bool
+	Filter ValidatorFilter
+}
+
+// ValidatorFilter — условия отбора обнаруженных валидаторов; незаданное поле — без ограничения.
+type ValidatorFilter struct {
+	// Минимальный стейк валидатора, MON; 0 — без ограничения.
+	MinStake utils.Amount `yaml:"minStake"`
+	// Максимальная комиссия, проценты; 0 — только валидаторы без комиссии, nil — без ограничения.
+	MaxCommission *float32 `yaml:"maxCommission"`
+	Exclude       []uint64 `yaml:"exclude"`
+}
+
+func (v *ValidatorsConfig) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value != ValidatorsAuto {
+			return fmt.Errorf("строка %d: validators должен быть списком ID, %s или фильтром", node.Line, ValidatorsAuto)
+		}
+		v.Auto = true
+		return nil
+	case yaml.SequenceNode:
+		return node.Decode(&v.IDs)
+	case yaml.MappingNode:
+		v.Auto = true
+		return node.Decode(&v.Filter)
+	default:
+		return fmt.Errorf("строка %d: validators должен быть списком ID, %s или фильтром", node.Line, ValidatorsAuto)
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidatorsConfigUnmarshal(t *testing.T) {
+	tests := []struct {
+		name              string
+		doc               string
+		wantIDs           int
+		wantAuto          bool
+		wantMaxCommission *float32
+	}{
+		{"list", "validators: [1, 2, 3]", 3, false, nil},
+		{"auto", "validators: auto", 0, true, nil},
+		{"filter without commission", "validators: {minStake: 1000}", 0, true, nil},
+		{"zero commission is a filter", "validators: {maxCommission: 0}", 0, true, ptr(float32(0))},
+		{"commission", "validators: {maxCommission: 7.5}", 0, true, ptr(float32(7.5))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc struct {
+				Validators ValidatorsConfig `yaml:"validators"`
+			}
+			if err := yaml.Unmarshal([]byte(tt.doc), &doc); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			v := doc.Validators
+			if len(v.IDs) != tt.wantIDs || v.Auto != tt.wantAuto {
+				t.Errorf("IDs %v, auto %v; want %d IDs, auto %v", v.IDs, v.Auto, tt.wantIDs, tt.wantAuto)
+			}
+
+			got := v.Filter.MaxCommission
+			if (got == nil) != (tt.wantMaxCommission == nil) || (got != nil && *got != *tt.wantMaxCommission) {
+				t.Errorf("maxCommission = %v, want %v", got, tt.wantMaxCommission)
+			}
+		})
+	}
+
+	var doc struct {
+		Validators ValidatorsConfig `yaml:"validators"`
+	}
+	if err := yaml.Unmarshal([]byte("validators: all"), &doc); err == nil {
+		t.Error("Unmarshal of unknown scalar succeeded")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
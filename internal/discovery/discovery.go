@@ -0,0 +1,168 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"ms/internal/client"
+	"os"
+	"strings"
+	"time"
+)
+
+// Source — вызовы стейкинг-контракта, нужные для обнаружения валидаторов.
+type Source interface {
+	GetConsensusValidatorSet(ctx context.Context, to string) ([]uint64, error)
+	GetValidator(ctx context.Context, to string, validatorID uint64) (client.Validator, error)
+}
+
+// Validator — снимок активного валидатора: флаги состояния, комиссия и стейк из getValidator.
+type Validator struct {
+	ID         uint64   `json:"id"`
+	Flags      uint64   `json:"flags"`
+	Stake      *big.Int `json:"stake"`
+	Commission *big.Int `json:"commission"`
+}
+
+// Status описывает флаги валидатора: ok или перечень проблем.
+func (v Validator) Status() string {
+	return FlagsStatus(v.Flags)
+}
+
+// FlagsStatus переводит флаги getValidator в читаемый вид.
+func FlagsStatus(flags uint64) string {
+	if flags == 0 {
+		return "ok"
+	}
+
+	var parts []string
+	for _, f := range []struct {
+		flag uint64
+		name string
+	}{
+		{client.ValidatorFlagStakeTooLow, "stake too low"},
+		{client.ValidatorFlagWithdrawn, "withdrawn"},
+		{client.ValidatorFlagDoubleSign, "double sign"},
+	} {
+		if flags&f.flag != 0 {
+			parts = append(parts, f.name)
+			flags &^= f.flag
+		}
+	}
+	if flags != 0 {
+		parts = append(parts, fmt.Sprintf("flags %#x", flags))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Discovery читает активный набор валидаторов стейкинг-контракта и кэширует его в файле на ttl.
+type Discovery struct {
+	source    Source
+	contract  string
+	cachePath string
+	ttl       time.Duration
+}
+
+type cacheFile struct {
+	Contract   string      `json:"contract"`
+	FetchedAt  time.Time   `json:"fetchedAt"`
+	Validators []Validator `json:"validators"`
+}
+
+func New(source Source, contract, cachePath string, ttl time.Duration) *Discovery {
+	return &Discovery{source: source, contract: contract, cachePath: cachePath, ttl: ttl}
+}
+
+// Validators возвращает активных валидаторов из кэша, если он моложе ttl, иначе из контракта.
+// Если контракт недоступен, используется устаревший кэш.
+func (d *Discovery) Validators(ctx context.Context) ([]Validator, error) {
+	cache, cacheErr := d.readCache()
+	if cacheErr == nil && time.Since(cache.FetchedAt) < d.ttl {
+		log.Printf("[INFO] Using %d cached validators from %s (fetched %s ago)", len(cache.Validators), d.cachePath, time.Since(cache.FetchedAt).Round(time.Second))
+		return cache.Validators, nil
+	}
+
+	validators, err := d.fetch(ctx)
+	if err != nil {
+		if cacheErr == nil {
+			log.Printf("[WARN] %v, using stale validator cache from %s", err, cache.FetchedAt.Format(time.RFC3339))
+			return cache.Validators, nil
+		}
+		return nil, err
+	}
+
+	if err := d.writeCache(validators); err != nil {
+		log.Printf("[WARN] failed to write validator cache: %v", err)
+	}
+
+	log.Printf("[INFO] Discovered %d active validators", len(validators))
+
+	return validators, nil
+}
+
+func (d *Discovery) fetch(ctx context.Context) ([]Validator, error) {
+	ids, err := d.source.GetConsensusValidatorSet(ctx, d.contract)
+	if err != nil {
+		return nil, err
+	}
+
+	validators := make([]Validator, 0, len(ids))
+	for _, id := range ids {
+		info, err := d.source.GetValidator(ctx, d.contract, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read validator %d: %w", id, err)
+		}
+
+		validators = append(validators, Validator{
+			ID:         id,
+			Flags:      info.Flags,
+			Stake:      info.Stake,
+			Commission: info.Commission,
+		})
+	}
+
+	return validators, nil
+}
+
+func (d *Discovery) readCache() (cacheFile, error) {
+	var cache cacheFile
+
+	data, err := os.ReadFile(d.cachePath)
+	if err != nil {
+		return cache, err
+	}
+
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cache, fmt.Errorf("ошибка разбора кэша валидаторов: %w", err)
+	}
+
+	// Кэш другого контракта не подходит
+	if !strings.EqualFold(cache.Contract, d.contract) {
+		return cache, errors.New("validator cache belongs to another contract")
+	}
+
+	return cache, nil
+}
+
+// writeCache пишет кэш через временный файл, чтобы прерванная запись не испортила старый кэш.
+func (d *Discovery) writeCache(validators []Validator) error {
+	data, err := json.MarshalIndent(cacheFile{
+		Contract:   d.contract,
+		FetchedAt:  time.Now().UTC(),
+		Validators: validators,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := d.cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, d.cachePath)
+}
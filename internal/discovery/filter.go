@@ -0,0 +1,40 @@
+package discovery
+
+import (
+	"math/big"
+)
+
+// Filter отбирает валидаторов из обнаруженного набора. Валидаторы с ненулевыми флагами
+// (недостаточный стейк, вывод, двойная подпись) отбрасываются всегда.
+type Filter struct {
+	// Минимальный стейк валидатора в wei; nil — без ограничения.
+	MinStake *big.Int
+	// Максимальная комиссия в единицах контракта (1e18 = 100%); nil — без ограничения.
+	MaxCommission *big.Int
+	Exclude       []uint64
+}
+
+// Apply возвращает ID подходящих валидаторов в порядке набора.
+func (f Filter) Apply(validators []Validator) []uint64 {
+	excluded := make(map[uint64]struct{}, len(f.Exclude))
+	for _, id := range f.Exclude {
+		excluded[id] = struct{}{}
+	}
+
+	var ids []uint64
+	for _, v := range validators {
+		if _, ok := excluded[v.ID]; ok || v.Flags != 0 {
+			continue
+		}
+		if f.MinStake != nil && (v.Stake == nil || v.Stake.Cmp(f.MinStake) < 0) {
+			continue
+		}
+		if f.MaxCommission != nil && (v.Commission == nil || v.Commission.Cmp(f.MaxCommission) > 0) {
+			continue
+		}
+
+		ids = append(ids, v.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,190 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"ms/pkg/utils"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Status string
+
+const (
+	StatusSigned  Status = "signed"
+	StatusSent    Status = "sent"
+	StatusSuccess Status = "success"
+	StatusFailed  Status = "failed"
+	// Аккаунт не стейкал: баланса не хватает на минимальную сумму и комиссию.
+	StatusSkipped Status = "skipped"
+)
+
+// Entry — одна запись журнала: очередное состояние стейка аккаунта в рамках запуска.
+type Entry struct {
+	RunID       string         `json:"runId"`
+	Address     common.Address `json:"address"`
+	Stake       utils.Amount   `json:"stake"`
+	ValidatorID uint64         `json:"validatorId"`
+	Nonce       uint64         `json:"nonce"`
+	TxHash      string         `json:"txHash,omitempty"`
+	Status      Status         `json:"status"`
+	Error       string         `json:"error,omitempty"`
+	Time        time.Time      `json:"time"`
+}
+
+// Journal — append-only JSON Lines журнал запуска. Последняя запись по адресу определяет его состояние.
+type Journal struct {
+	mu    sync.Mutex
+	file  *os.File
+	runID string
+	last  map[common.Address]Entry
+}
+
+// Open открывает журнал и продолжает последний записанный в нем запуск.
+// При newRun (или пустом журнале) начинается новый запуск.
+func Open(path string, newRun bool) (*Journal, error) {
+	entries, keep, needNewline, err := readEntries(path)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Journal{last: make(map[common.Address]Entry)}
+
+	if newRun || len(entries) == 0 {
+		j.runID = time.Now().UTC().Format("20060102T150405Z")
+	} else {
+		j.runID = entries[len(entries)-1].RunID
+	}
+
+	for _, e := range entries {
+		if e.RunID == j.runID {
+			j.last[e.Address] = e
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия журнала: %w", err)
+	}
+
+	// Оборванный хвост отрезается, а последняя запись без перевода строки дополняется им,
+	// чтобы новые записи не склеились с ними
+	if err := file.Truncate(keep); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("ошибка восстановления журнала: %w", err)
+	}
+	if needNewline {
+		if _, err := file.Write([]byte{'\n'}); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("ошибка восстановления журнала: %w", err)
+		}
+	}
+	j.file = file
+
+	return j, nil
+}
+
+// readEntries читает записи журнала и возвращает длину его корректной части. Если процесс упал
+// посреди записи, последняя строка оборвана: она отбрасывается с предупреждением. Ошибка в любой
+// другой строке — повреждение журнала. needNewline — последняя запись не завершена переводом строки.
+func readEntries(path string) (entries []Entry, keep int64, needNewline bool, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("ошибка чтения журнала: %w", err)
+	}
+
+	for line := 1; len(data) > 0; line++ {
+		raw, rest, found := bytes.Cut(data, []byte{'\n'})
+
+		if len(bytes.TrimSpace(raw)) > 0 {
+			var e Entry
+			if err := json.Unmarshal(raw, &e); err != nil {
+				if len(bytes.TrimSpace(rest)) > 0 {
+					return nil, 0, false, fmt.Errorf("ошибка разбора журнала в строке %d: %w", line, err)
+				}
+
+				log.Printf("[WARN] dropping truncated last line %d of journal %s: %v", line, path, err)
+				return entries, keep, false, nil
+			}
+
+			entries = append(entries, e)
+			needNewline = !found
+		}
+
+		keep += int64(len(raw))
+		if found {
+			keep++
+		}
+		data = rest
+	}
+
+	return entries, keep, needNewline, nil
+}
+
+func (j *Journal) RunID() string {
+	if j == nil {
+		return ""
+	}
+
+	return j.runID
+}
+
+// Last возвращает последнюю запись по адресу в текущем запуске.
+func (j *Journal) Last(addr common.Address) (Entry, bool) {
+	if j == nil {
+		return Entry{}, false
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e, ok := j.last[addr]
+	return e, ok
+}
+
+// Record дописывает запись в журнал и сбрасывает ее на диск. Nil-журнал ничего не пишет.
+func (j *Journal) Record(e Entry) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e.RunID = j.runID
+	e.Time = time.Now().UTC()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync journal: %w", err)
+	}
+
+	j.last[e.Address] = e
+
+	return nil
+}
+
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+
+	return j.file.Close()
+}
@@ -0,0 +1,104 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	line1 = `{"runId":"r1","address":"0x0000000000000000000000000000000000000001","stake":"1.5","validatorId":1,"nonce":0,"status":"sent"}`
+	line2 = `{"runId":"r1","address":"0x0000000000000000000000000000000000000002","stake":"2","validatorId":1,"nonce":3,"status":"success"}`
+)
+
+func writeJournal(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestOpenRecoversTail(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"clean", line1 + "\n" + line2 + "\n"},
+		{"truncated last line", line1 + "\n" + line2 + "\n" + line2[:40]},
+		{"truncated last line with newline", line1 + "\n" + line2 + "\n" + line2[:40] + "\n"},
+		{"last line without newline", line1 + "\n" + line2},
+		{"blank lines", line1 + "\n\n" + line2 + "\n\n"},
+	}
+
+	addr1, addr2 := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	addr3 := common.HexToAddress("0x3")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeJournal(t, tt.content)
+
+			j, err := Open(path, false)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if j.RunID() != "r1" {
+				t.Fatalf("run %q, want r1", j.RunID())
+			}
+			if e, ok := j.Last(addr2); !ok || e.Status != StatusSuccess || e.Nonce != 3 {
+				t.Fatalf("last entry of %s = %+v, %v", addr2.Hex(), e, ok)
+			}
+
+			// Новая запись не должна склеиться с оборванным хвостом
+			if err := j.Record(Entry{Address: addr3, Status: StatusSigned}); err != nil {
+				t.Fatalf("Record: %v", err)
+			}
+			j.Close()
+
+			j, err = Open(path, false)
+			if err != nil {
+				data, _ := os.ReadFile(path)
+				t.Fatalf("reopen: %v\n%s", err, data)
+			}
+			defer j.Close()
+
+			for _, addr := range []common.Address{addr1, addr2, addr3} {
+				if _, ok := j.Last(addr); !ok {
+					t.Errorf("no entry for %s after reopen", addr.Hex())
+				}
+			}
+		})
+	}
+}
+
+func TestOpenFailsOnCorruptMiddleLine(t *testing.T) {
+	path := writeJournal(t, line1+"\n"+line2[:40]+"\n"+line2+"\n")
+
+	_, err := Open(path, false)
+	if err == nil || !strings.Contains(err.Error(), "строке 2") {
+		t.Fatalf("Open error = %v, want parse error at line 2", err)
+	}
+}
+
+func TestOpenMissingFileStartsNewRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+
+	j, err := Open(path, false)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer j.Close()
+
+	if j.RunID() == "" {
+		t.Fatal("empty run ID")
+	}
+	if _, ok := j.Last(common.HexToAddress("0x1")); ok {
+		t.Fatal("unexpected entry in a new journal")
+	}
+}
@@ -0,0 +1,200 @@
+package keys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
+
+	bundleVersion = 1
+	keyLen        = 32
+	saltLen       = 32
+
+	// Параметры как у "standard" V3 keystore: ~1 секунда на разблокировку
+	scryptN = 1 << 18
+	scryptR = 8
+	scryptP = 1
+
+	argon2Time    = 3
+	argon2Memory  = 256 * 1024
+	argon2Threads = 4
+)
+
+var ErrWrongPassphrase = errors.New("неверный пароль или поврежденный файл ключей")
+
+// Bundle — зашифрованный файл ключей: содержимое обычного файла с приватными ключами,
+// зашифрованное AES-256-GCM ключом, выведенным из пароля через scrypt или argon2id.
+type Bundle struct {
+	Version    int       `json:"version"`
+	KDF        string    `json:"kdf"`
+	KDFParams  KDFParams `json:"kdfparams"`
+	Nonce      string    `json:"nonce"`
+	Ciphertext string    `json:"ciphertext"`
+}
+
+type KDFParams struct {
+	Salt string `json:"salt"`
+
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+
+	// argon2id
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// Encrypt шифрует plaintext паролем passphrase, выводя ключ через kdf (scrypt или argon2id).
+func Encrypt(plaintext []byte, passphrase, kdf string) ([]byte, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	params := KDFParams{Salt: hex.EncodeToString(salt)}
+	switch kdf {
+	case KDFScrypt:
+		params.N, params.R, params.P = scryptN, scryptR, scryptP
+	case KDFArgon2id:
+		params.Time, params.Memory, params.Threads = argon2Time, argon2Memory, argon2Threads
+	default:
+		return nil, fmt.Errorf("неизвестная функция вывода ключа %q, ожидается %s или %s", kdf, KDFScrypt, KDFArgon2id)
+	}
+
+	key, err := deriveKey(passphrase, kdf, params)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return json.MarshalIndent(Bundle{
+		Version:    bundleVersion,
+		KDF:        kdf,
+		KDFParams:  params,
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, plaintext, []byte(kdf))),
+	}, "", "  ")
+}
+
+// Decrypt расшифровывает файл, созданный Encrypt.
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("ошибка разбора зашифрованного файла ключей: %w", err)
+	}
+
+	if b.Version != bundleVersion {
+		return nil, fmt.Errorf("неподдерживаемая версия зашифрованного файла ключей: %d", b.Version)
+	}
+
+	key, err := deriveKey(passphrase, b.KDF, b.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := hex.DecodeString(b.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("некорректный nonce в зашифрованном файле ключей")
+	}
+
+	ciphertext, err := hex.DecodeString(b.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный шифротекст в зашифрованном файле ключей: %w", err)
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(b.KDF))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	return plaintext, nil
+}
+
+// ReadBundle читает и расшифровывает зашифрованный файл ключей.
+func ReadBundle(path, passphrase string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия зашифрованного файла ключей: %w", err)
+	}
+
+	return Decrypt(data, passphrase)
+}
+
+// WriteBundle шифрует plaintext и записывает его в path с правами только для владельца.
+func WriteBundle(path string, plaintext []byte, passphrase, kdf string) error {
+	data, err := Encrypt(plaintext, passphrase, kdf)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("ошибка записи зашифрованного файла ключей: %w", err)
+	}
+
+	return nil
+}
+
+func deriveKey(passphrase, kdf string, params KDFParams) ([]byte, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("некорректная соль в зашифрованном файле ключей")
+	}
+
+	switch kdf {
+	case KDFScrypt:
+		key, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, keyLen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+		return key, nil
+	case KDFArgon2id:
+		if params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+			return nil, fmt.Errorf("некорректные параметры argon2id")
+		}
+		return argon2.IDKey([]byte(passphrase), salt, params.Time, params.Memory, params.Threads, keyLen), nil
+	default:
+		return nil, fmt.Errorf("неизвестная функция вывода ключа %q", kdf)
+	}
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cipher: %w", err)
+	}
+
+	return aead, nil
+}
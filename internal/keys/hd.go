@@ -0,0 +1,107 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// IndexPlaceholder заменяется в шаблоне пути деривации на индекс аккаунта.
+const IndexPlaceholder = "{index}"
+
+// DefaultPathTemplate — путь BIP-44 для Ethereum, как в MetaMask и большинстве кошельков.
+const DefaultPathTemplate = "m/44'/60'/0'/0/" + IndexPlaceholder
+
+// HDWallet выводит ключи из BIP-39 мнемоники по BIP-32.
+type HDWallet struct {
+	masterKey   []byte
+	masterChain []byte
+}
+
+// NewHDWallet проверяет мнемонику (слова и контрольную сумму) и вычисляет мастер-ключ.
+func NewHDWallet(mnemonic, passphrase string) (*HDWallet, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("некорректная мнемоника: неизвестные слова или неверная контрольная сумма")
+	}
+
+	seed := bip39.NewSeed(mnemonic, passphrase)
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	return &HDWallet{masterKey: sum[:32], masterChain: sum[32:]}, nil
+}
+
+// DerivationPath подставляет индекс в шаблон пути, например m/44'/60'/0'/0/{index}.
+func DerivationPath(template string, index uint32) (accounts.DerivationPath, error) {
+	if !strings.Contains(template, IndexPlaceholder) {
+		return nil, fmt.Errorf("шаблон пути деривации %q не содержит %s", template, IndexPlaceholder)
+	}
+
+	path, err := accounts.ParseDerivationPath(strings.ReplaceAll(template, IndexPlaceholder, strconv.FormatUint(uint64(index), 10)))
+	if err != nil {
+		return nil, fmt.Errorf("некорректный путь деривации %q: %w", template, err)
+	}
+
+	return path, nil
+}
+
+// Derive выводит приватный ключ по пути path.
+func (w *HDWallet) Derive(path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	key, chain := w.masterKey, w.masterChain
+
+	for _, index := range path {
+		var err error
+		if key, chain, err = deriveChild(key, chain, index); err != nil {
+			return nil, fmt.Errorf("failed to derive %s: %w", path, err)
+		}
+	}
+
+	return crypto.ToECDSA(key)
+}
+
+// deriveChild — CKDpriv из BIP-32.
+func deriveChild(key, chain []byte, index uint32) ([]byte, []byte, error) {
+	var data []byte
+	if index >= 0x80000000 {
+		data = append([]byte{0}, key...)
+	} else {
+		priv, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&priv.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chain)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, nil, errors.New("invalid child key")
+	}
+
+	child := il.Add(il, new(big.Int).SetBytes(key))
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, errors.New("invalid child key")
+	}
+
+	return math.PaddedBigBytes(child, 32), sum[32:], nil
+}
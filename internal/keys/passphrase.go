@@ -0,0 +1,56 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Passphrase берет пароль из переменной окружения env, а если она пуста — спрашивает в терминале.
+// С confirm пароль вводится дважды (для шифрования новых файлов).
+func Passphrase(env, prompt string, confirm bool) (string, error) {
+	if env != "" {
+		if pass := os.Getenv(env); pass != "" {
+			return pass, nil
+		}
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("пароль не задан: установите переменную окружения %s", env)
+	}
+
+	pass, err := readPassword(fd, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	if pass == "" {
+		return "", errors.New("пароль не может быть пустым")
+	}
+
+	if confirm {
+		repeat, err := readPassword(fd, "Повторите пароль: ")
+		if err != nil {
+			return "", err
+		}
+		if repeat != pass {
+			return "", errors.New("пароли не совпадают")
+		}
+	}
+
+	return pass, nil
+}
+
+func readPassword(fd int, prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	pass, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("ошибка чтения пароля: %w", err)
+	}
+
+	return string(pass), nil
+}
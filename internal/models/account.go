@@ -2,78 +2,257 @@ package models
 
 import (
 	"bufio"
-	"crypto/ecdsa"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"ms/internal/client"
+	"ms/internal/keys"
 	"ms/pkg/utils"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
 type Account struct {
-	Address    common.Address
-	PrivateKey *ecdsa.PrivateKey
+	Address common.Address
+	// Подписывает транзакции аккаунта; ключ может находиться вне процесса (см. client.Signer).
+	Signer client.Signer
+	// Для HD кошелька — индекс деривации, иначе — порядковый номер ключа в источнике (с 0).
+	Index uint32
+	// Метка из файла ключей (текст после #), может быть пустой.
+	Label string
 }
 
-// LoadAccountsFromFile загружает аккаунты из файла с приватными ключами
-func LoadAccountsFromFile(filePath string) ([]Account, error) {
+// String возвращает короткое имя аккаунта для логов и отчетов: "#17 0x1234abcd" или "#17 alice-3 0x1234abcd".
+func (a Account) String() string {
+	if a.Label != "" {
+		return fmt.Sprintf("#%d %s %s", a.Index, a.Label, a.Address.Hex()[:10])
+	}
+	return fmt.Sprintf("#%d %s", a.Index, a.Address.Hex()[:10])
+}
+
+// LoadAccountsFromFile загружает аккаунты из файла с приватными ключами.
+// Некорректные и повторяющиеся ключи пропускаются и попадают в отчет.
+func LoadAccountsFromFile(filePath string) ([]Account, LoadReport, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка открытия файла с приватными ключами: %w", err)
+		return nil, LoadReport{Source: filePath}, fmt.Errorf("ошибка открытия файла с приватными ключами: %w", err)
 	}
 	defer file.Close()
 
-	var privateKeys []string
-	scanner := bufio.NewScanner(file)
+	return parseKeyFile(file, filePath)
+}
+
+// LoadAccountsFromBundle загружает аккаунты из зашифрованного файла ключей (см. keys.WriteBundle).
+func LoadAccountsFromBundle(filePath, passphrase string) ([]Account, LoadReport, error) {
+	plaintext, err := keys.ReadBundle(filePath, passphrase)
+	if err != nil {
+		return nil, LoadReport{Source: filePath}, err
+	}
+
+	return parseKeyFile(bytes.NewReader(plaintext), filePath)
+}
+
+// LoadAccountsFromKeystore загружает аккаунты из V3 keystore файлов каталога dir.
+// Все файлы должны быть зашифрованы одним паролем; ключи расшифровываются только на время подписи.
+func LoadAccountsFromKeystore(dir, passphrase string) ([]Account, LoadReport, error) {
+	report := LoadReport{Source: dir}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, report, fmt.Errorf("ошибка открытия каталога keystore: %w", err)
+	}
+
+	var (
+		accounts []Account
+		seen     = make(map[common.Address]string)
+	)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
-		privateKeys = append(privateKeys, line)
-	}
+		signer, err := client.NewKeystoreSigner(filepath.Join(dir, entry.Name()), passphrase)
+		if err != nil {
+			report.skip(SkippedKey{File: entry.Name(), Reason: ReasonDecrypt, Detail: err.Error()})
+			continue
+		}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("ошибка чтения файла: %w", err)
+		if prev, ok := seen[signer.Address()]; ok {
+			report.skip(SkippedKey{File: entry.Name(), Reason: ReasonDuplicate, Detail: "same as " + prev})
+			continue
+		}
+		seen[signer.Address()] = entry.Name()
+
+		accounts = append(accounts, Account{
+			Address: signer.Address(),
+			Signer:  signer,
+			Index:   uint32(len(accounts)),
+		})
 	}
 
-	if len(privateKeys) == 0 {
-		return nil, fmt.Errorf("файл не содержит приватных ключей")
+	report.Loaded = len(accounts)
+	if len(accounts) == 0 {
+		return nil, report, fmt.Errorf("каталог keystore не содержит ключей")
 	}
 
-	return CreateAccounts(privateKeys...), nil
+	return accounts, report, nil
 }
 
-func CreateAccounts(privateKeys ...string) []Account {
-	accounts := make([]Account, 0, len(privateKeys))
-	for _, pk := range privateKeys {
-		acc, err := processPrivateKey(pk)
+// LoadAccountsFromMnemonic выводит аккаунты с индексами from..to (включительно) из BIP-39 мнемоники
+// по шаблону пути деривации, например m/44'/60'/0'/0/{index}.
+func LoadAccountsFromMnemonic(mnemonic, pathTemplate string, from, to uint32) ([]Account, LoadReport, error) {
+	report := LoadReport{Source: "mnemonic"}
+
+	if to < from {
+		return nil, report, fmt.Errorf("некорректный диапазон индексов деривации: %d..%d", from, to)
+	}
+
+	wallet, err := keys.NewHDWallet(mnemonic, "")
+	if err != nil {
+		return nil, report, err
+	}
+
+	accounts := make([]Account, 0, to-from+1)
+	for index := uint64(from); index <= uint64(to); index++ {
+		path, err := keys.DerivationPath(pathTemplate, uint32(index))
 		if err != nil {
-			log.Fatalf("failed to parse private key: %v", err)
+			return nil, report, err
 		}
 
-		accounts = append(accounts, acc)
+		priv, err := wallet.Derive(path)
+		if err != nil {
+			return nil, report, err
+		}
+
+		addr, err := utils.DeriveAddress(priv)
+		if err != nil {
+			return nil, report, err
+		}
+
+		accounts = append(accounts, Account{
+			Address: addr,
+			Signer:  client.NewKeySigner(priv),
+			Index:   uint32(index),
+		})
 	}
 
-	return accounts
+	report.Loaded = len(accounts)
+	return accounts, report, nil
 }
 
-func processPrivateKey(input string) (Account, error) {
-	priv, err := utils.ParsePrivateKey(input)
+// LoadAccountsFromRemoteSigner создает аккаунты, подписывающие через внешний сервис (Clef, web3signer).
+// Если addresses пуст, берутся все адреса, которыми управляет сервис.
+func LoadAccountsFromRemoteSigner(ctx context.Context, url string, addresses []common.Address) ([]Account, LoadReport, error) {
+	report := LoadReport{Source: url}
+
+	cl, err := client.DialRemoteSigner(ctx, url)
 	if err != nil {
-		return Account{}, err
+		return nil, report, err
 	}
 
-	addr, err := utils.DeriveAddress(priv)
-	if err != nil {
-		return Account{}, err
+	if len(addresses) == 0 {
+		if addresses, err = client.RemoteAccounts(ctx, cl); err != nil {
+			return nil, report, err
+		}
 	}
 
-	return Account{
-		Address:    addr,
-		PrivateKey: priv,
-	}, nil
+	accounts := make([]Account, 0, len(addresses))
+	seen := make(map[common.Address]struct{}, len(addresses))
+	for i, addr := range addresses {
+		if _, ok := seen[addr]; ok {
+			report.skip(SkippedKey{File: fmt.Sprintf("addresses[%d]", i), Reason: ReasonDuplicate, Detail: addr.Hex()[:10]})
+			continue
+		}
+		seen[addr] = struct{}{}
+
+		accounts = append(accounts, Account{
+			Address: addr,
+			Signer:  client.NewRemoteSigner(cl, addr),
+			Index:   uint32(i),
+		})
+	}
+
+	report.Loaded = len(accounts)
+	if len(accounts) == 0 {
+		return nil, report, fmt.Errorf("сервис подписи %s не управляет ни одним адресом", url)
+	}
+
+	return accounts, report, nil
+}
+
+// parseKeyFile разбирает файл ключей: по одному ключу на строку, пустые строки и строки,
+// начинающиеся с #, пропускаются, а текст после # становится меткой аккаунта (0xabc... # alice-3).
+// Index аккаунта — порядковый номер ключа в файле, поэтому пропущенные ключи не сдвигают номера.
+func parseKeyFile(r io.Reader, source string) ([]Account, LoadReport, error) {
+	report := LoadReport{Source: source}
+
+	var (
+		accounts []Account
+		keyIndex uint32
+		seen     = make(map[common.Address]int)
+	)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		key, label, _ := strings.Cut(scanner.Text(), "#")
+		key, label = strings.TrimSpace(key), strings.TrimSpace(label)
+		if key == "" {
+			continue
+		}
+
+		index := keyIndex
+		keyIndex++
+
+		priv, err := utils.ParsePrivateKey(key)
+		if err != nil {
+			report.skip(SkippedKey{Line: line, Reason: keyErrorReason(err), Detail: label})
+			continue
+		}
+
+		addr, err := utils.DeriveAddress(priv)
+		if err != nil {
+			report.skip(SkippedKey{Line: line, Reason: ReasonInvalid, Detail: label})
+			continue
+		}
+
+		if prev, ok := seen[addr]; ok {
+			report.skip(SkippedKey{Line: line, Reason: ReasonDuplicate, Detail: fmt.Sprintf("%s, same as line %d", addr.Hex()[:10], prev)})
+			continue
+		}
+		seen[addr] = line
+
+		accounts = append(accounts, Account{
+			Address: addr,
+			Signer:  client.NewKeySigner(priv),
+			Index:   index,
+			Label:   label,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, report, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+
+	report.Loaded = len(accounts)
+	if len(accounts) == 0 {
+		return nil, report, fmt.Errorf("файл не содержит приватных ключей")
+	}
+
+	return accounts, report, nil
+}
+
+func keyErrorReason(err error) SkipReason {
+	switch {
+	case errors.Is(err, utils.ErrKeyLength):
+		return ReasonBadLength
+	case errors.Is(err, utils.ErrKeyNotHex):
+		return ReasonNotHex
+	default:
+		return ReasonInvalid
+	}
 }
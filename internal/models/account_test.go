@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Мнемоника и адреса тестовых аккаунтов Hardhat (m/44'/60'/0'/0/{index}).
+const hardhatMnemonic = "test test test test test test test test test test test junk"
+
+var hardhatAddresses = []string{
+	"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+	"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+	"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+	"0x90F79bf6EB2c4f870365E785982E1f101E93b906",
+	"0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65",
+}
+
+func TestLoadAccountsFromMnemonic(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to uint32
+	}{
+		{"first account", 0, 0},
+		{"range from zero", 0, 4},
+		{"range with offset", 2, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts, report, err := LoadAccountsFromMnemonic(hardhatMnemonic, "m/44'/60'/0'/0/{index}", tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("LoadAccountsFromMnemonic: %v", err)
+			}
+
+			if want := int(tt.to-tt.from) + 1; len(accounts) != want || report.Loaded != want {
+				t.Fatalf("got %d accounts (report %d), want %d", len(accounts), report.Loaded, want)
+			}
+
+			for i, acc := range accounts {
+				index := tt.from + uint32(i)
+				if acc.Index != index {
+					t.Errorf("account %d: index %d, want %d", i, acc.Index, index)
+				}
+				if want := common.HexToAddress(hardhatAddresses[index]); acc.Address != want {
+					t.Errorf("index %d: address %s, want %s", index, acc.Address.Hex(), want.Hex())
+				}
+				if acc.Signer.Address() != acc.Address {
+					t.Errorf("index %d: signer address %s differs from account %s", index, acc.Signer.Address().Hex(), acc.Address.Hex())
+				}
+			}
+		})
+	}
+}
+
+func TestLoadAccountsFromMnemonicErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		path     string
+		from, to uint32
+	}{
+		{"bad checksum", "test test test test test test test test test test test test", "m/44'/60'/0'/0/{index}", 0, 0},
+		{"unknown word", "test test test test test test test test test test test junkk", "m/44'/60'/0'/0/{index}", 0, 0},
+		{"no index placeholder", hardhatMnemonic, "m/44'/60'/0'/0/0", 0, 0},
+		{"reversed range", hardhatMnemonic, "m/44'/60'/0'/0/{index}", 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := LoadAccountsFromMnemonic(tt.mnemonic, tt.path, tt.from, tt.to); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"log"
+)
+
+type SkipReason string
+
+const (
+	ReasonBadLength SkipReason = "bad length"
+	ReasonNotHex    SkipReason = "non-hex"
+	ReasonInvalid   SkipReason = "invalid key"
+	ReasonDuplicate SkipReason = "duplicate address"
+	ReasonDecrypt   SkipReason = "cannot decrypt"
+)
+
+// SkippedKey — ключ, который не удалось загрузить. Сам ключ в отчет не попадает.
+type SkippedKey struct {
+	// Номер строки в файле ключей (с 1); 0 — для источников без строк.
+	Line int
+	// Имя файла для каталога keystore.
+	File   string
+	Reason SkipReason
+	Detail string
+}
+
+func (k SkippedKey) String() string {
+	where := k.File
+	if k.Line > 0 {
+		where = fmt.Sprintf("line %d", k.Line)
+	}
+
+	if k.Detail == "" {
+		return fmt.Sprintf("%s: %s", where, k.Reason)
+	}
+	return fmt.Sprintf("%s: %s (%s)", where, k.Reason, k.Detail)
+}
+
+// LoadReport — итог загрузки аккаунтов из одного источника.
+type LoadReport struct {
+	Source  string
+	Loaded  int
+	Skipped []SkippedKey
+}
+
+func (r *LoadReport) skip(k SkippedKey) {
+	r.Skipped = append(r.Skipped, k)
+}
+
+// Log печатает сводку загрузки и каждый пропущенный ключ.
+func (r LoadReport) Log() {
+	log.Printf("[INFO] Accounts from %s: %d loaded, %d skipped", r.Source, r.Loaded, len(r.Skipped))
+
+	for _, k := range r.Skipped {
+		log.Printf("[WARN] skipped key at %s", k)
+	}
+}
+
+// Err возвращает ошибку, если хотя бы один ключ пропущен (строгий режим).
+func (r LoadReport) Err() error {
+	if len(r.Skipped) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%d ключей из %s не загружено, первый: %s", len(r.Skipped), r.Source, r.Skipped[0])
+}
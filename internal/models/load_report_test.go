@@ -0,0 +1,138 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Приватные ключи тестовых аккаунтов Hardhat 0..2 (адреса — hardhatAddresses).
+var hardhatKeys = []string{
+	"0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+	"59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+	"0x5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a",
+}
+
+func writeKeyFile(t *testing.T, lines ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadAccountsFromFile(t *testing.T) {
+	path := writeKeyFile(t,
+		"# ключи стенда",
+		"",
+		hardhatKeys[0]+"  # alice-1",
+		"0x1234",                          // слишком короткий
+		strings.Repeat("zz", 32)+" # bob", // не hex
+		hardhatKeys[1],
+		"  "+hardhatKeys[0]+" # alice-1 again", // повтор
+		hardhatKeys[2]+" #carol # main",
+	)
+
+	accounts, report, err := LoadAccountsFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadAccountsFromFile: %v", err)
+	}
+
+	want := []struct {
+		addr  string
+		index uint32
+		label string
+	}{
+		// Пропущенные ключи не сдвигают номера следующих
+		{hardhatAddresses[0], 0, "alice-1"},
+		{hardhatAddresses[1], 3, ""},
+		{hardhatAddresses[2], 5, "carol # main"},
+	}
+	if len(accounts) != len(want) || report.Loaded != len(want) {
+		t.Fatalf("loaded %d accounts (report %d), want %d", len(accounts), report.Loaded, len(want))
+	}
+	for i, w := range want {
+		acc := accounts[i]
+		if acc.Address != common.HexToAddress(w.addr) || acc.Index != w.index || acc.Label != w.label {
+			t.Errorf("account %d = %s index %d label %q; want %s index %d label %q", i, acc.Address.Hex(), acc.Index, acc.Label, w.addr, w.index, w.label)
+		}
+	}
+
+	wantSkipped := []struct {
+		line   int
+		reason SkipReason
+	}{
+		{4, ReasonBadLength},
+		{5, ReasonNotHex},
+		{7, ReasonDuplicate},
+	}
+	if len(report.Skipped) != len(wantSkipped) {
+		t.Fatalf("skipped %v, want %d keys", report.Skipped, len(wantSkipped))
+	}
+	for i, w := range wantSkipped {
+		if k := report.Skipped[i]; k.Line != w.line || k.Reason != w.reason {
+			t.Errorf("skipped %d = line %d %s; want line %d %s", i, k.Line, k.Reason, w.line, w.reason)
+		}
+	}
+
+	// Отчет не раскрывает ключи
+	for _, k := range report.Skipped {
+		if strings.Contains(k.String(), strings.TrimPrefix(hardhatKeys[0], "0x")) {
+			t.Errorf("skipped key report leaks the key: %s", k)
+		}
+	}
+	if !strings.Contains(report.Skipped[1].String(), "line 5: non-hex (bob)") {
+		t.Errorf("skipped key = %q, want line, reason and label", report.Skipped[1])
+	}
+}
+
+func TestLoadReportModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      []string
+		wantStrict bool // строгий режим останавливает загрузку
+	}{
+		{"all keys valid", []string{hardhatKeys[0], hardhatKeys[1]}, false},
+		{"invalid key", []string{hardhatKeys[0], "0x1234"}, true},
+		{"duplicate key", []string{hardhatKeys[0], hardhatKeys[0]}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Нестрогий режим: загрузка успешна, пропуски только в отчете
+			accounts, report, err := LoadAccountsFromFile(writeKeyFile(t, tt.lines...))
+			if err != nil {
+				t.Fatalf("LoadAccountsFromFile: %v", err)
+			}
+			if len(accounts)+len(report.Skipped) != len(tt.lines) {
+				t.Errorf("%d loaded + %d skipped, want %d keys", len(accounts), len(report.Skipped), len(tt.lines))
+			}
+
+			err = report.Err()
+			if (err != nil) != tt.wantStrict {
+				t.Fatalf("Err() = %v, want error %v", err, tt.wantStrict)
+			}
+			if err != nil && !strings.Contains(err.Error(), "line 2") {
+				t.Errorf("Err() = %q, want first skipped key", err)
+			}
+		})
+	}
+}
+
+func TestLoadAccountsFromFileWithoutKeys(t *testing.T) {
+	for name, lines := range map[string][]string{
+		"empty":         {},
+		"comments only": {"# alice", "", "   # bob"},
+		"all invalid":   {"0x1234", strings.Repeat("zz", 32)},
+	} {
+		if _, _, err := LoadAccountsFromFile(writeKeyFile(t, lines...)); err == nil {
+			t.Errorf("%s: LoadAccountsFromFile succeeded, want error", name)
+		}
+	}
+}
@@ -1,15 +1,104 @@
 package service
 
+import (
+	"math/big"
+	"ms/internal/models"
+	"ms/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
 type (
 	RunParams struct {
-		Stake           Range
+		// Для SizingRange — диапазон случайной суммы, для остальных стратегий — минимум и потолок (Max 0 — без потолка).
+		Stake           AmountRange
+		Sizing          Sizing
 		Delay           Range
-		Validators      []uint8
+		Validators      []uint64
+		Selection       ValidatorSelection
 		ContractAddress string
+
+		// Интервал (в секундах) между проверками эпохи в режиме unstake.
+		WithdrawPollInterval float32
+
+		// Только симуляция для stake, fund и sweep: транзакции подписываются (stake — еще и проверяется
+		// через eth_call), но не отправляются. Остальные режимы dry-run не поддерживают.
+		DryRun bool
+
+		// Настройки отдельных аккаунтов; ключ — адрес или метка аккаунта в нижнем регистре.
+		Overrides map[string]AccountOverride
+	}
+
+	// AccountOverride — настройки аккаунта поверх общих RunParams; nil-поля не переопределяются.
+	AccountOverride struct {
+		Stake      *AmountRange
+		Delay      *Range
+		Validators []uint64
+		Enabled    *bool
+	}
+
+	// Sizing — стратегия выбора суммы стейка.
+	Sizing struct {
+		Strategy SizingStrategy
+		// Сумма для SizingFixed.
+		Amount utils.Amount
+		// Процент свободного баланса для SizingPercent.
+		Percent float32
+		// Остаток на аккаунте для SizingAllButReserve.
+		Reserve utils.Amount
+		// Шаг случайной суммы для SizingRange.
+		Granularity utils.Amount
+	}
+
+	SizingStrategy string
+
+	// ValidatorSelection — стратегия выбора валидатора для аккаунта.
+	ValidatorSelection struct {
+		Strategy SelectionStrategy
+		// Веса валидаторов для SelectWeighted; валидатор без веса имеет вес 1.
+		Weights map[uint64]uint64
+	}
+
+	SelectionStrategy string
+
+	// FundParams — пополнение аккаунтов с казначейского адреса (команда fund).
+	FundParams struct {
+		// Баланс, до которого пополняется каждый аккаунт; не используется, если задан Amount.
+		TargetBalance utils.Amount
+		// Фиксированная сумма перевода каждому аккаунту.
+		Amount utils.Amount
+		// Предел расхода за запуск: суммы переводов плюс максимальные комиссии.
+		SpendCap utils.Amount
+		// Сколько переводов одновременно ждут включения в блок.
+		MaxInFlight int
+	}
+
+	// SweepParams — сбор свободных балансов аккаунтов на казначейский адрес (команда sweep).
+	SweepParams struct {
+		Treasury common.Address
+		// Аккаунты, у которых после вычета комиссии остается меньше, не переводятся.
+		MinAmount utils.Amount
+		// Адреса или метки аккаунтов, которые не нужно трогать.
+		Exclude []string
+		// Задержка между аккаунтами вместо RunParams.Delay.
+		Delay Range
+	}
+
+	pendingWithdrawal struct {
+		account       models.Account
+		validatorID   uint64
+		withdrawID    uint8
+		amount        *big.Int
+		withdrawEpoch uint64
 	}
 
 	Range struct {
 		Min float32
 		Max float32
 	}
+
+	AmountRange struct {
+		Min utils.Amount
+		Max utils.Amount
+	}
 )
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"ms/internal/client"
+	"ms/internal/discovery"
+	"ms/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type RejectReason string
+
+const (
+	// Валидатора нет в стейкинг-контракте (удален или неверный ID).
+	RejectNotRegistered RejectReason = "not registered"
+	// Стейк ниже порога или валидатор вышел (флаги StakeTooLow, Withdrawn).
+	RejectInactive RejectReason = "inactive"
+	// Валидатор наказан за двойную подпись.
+	RejectJailed RejectReason = "jailed"
+)
+
+// ValidatorRejection — валидатор не подходит для delegate: транзакция к нему откатилась бы после траты газа.
+type ValidatorRejection struct {
+	ValidatorID uint64
+	Reason      RejectReason
+	// Флаги getValidator, если причина в них.
+	Flags uint64
+}
+
+func (r ValidatorRejection) Error() string {
+	if r.Flags == 0 {
+		return fmt.Sprintf("validator %d is %s", r.ValidatorID, r.Reason)
+	}
+	return fmt.Sprintf("validator %d is %s (%s)", r.ValidatorID, r.Reason, discovery.FlagsStatus(r.Flags))
+}
+
+// errNoEligibleValidator — все кандидаты аккаунта отклонены в этом запуске.
+var errNoEligibleValidator = errors.New("no eligible validator")
+
+// pickValidator выбирает валидатора селектором и проверяет его состояние в контракте. Отклоненный
+// валидатор запоминается до конца запуска, и выбор повторяется среди оставшихся кандидатов.
+func (s *staker) pickValidator(ctx context.Context, cfg RunParams, selector ValidatorSelector, acc models.Account) (uint64, error) {
+	candidates := s.withoutRejected(cfg.Validators)
+
+	for len(candidates) > 0 {
+		id, err := selector.Select(ctx, acc, candidates)
+		if err != nil {
+			return 0, err
+		}
+
+		err = s.checkValidator(ctx, cfg, id)
+		var rejection ValidatorRejection
+		if !errors.As(err, &rejection) {
+			return id, err
+		}
+
+		s.reject(rejection)
+		candidates = s.withoutRejected(candidates)
+	}
+
+	return 0, fmt.Errorf("%w: all of %v are rejected in this run", errNoEligibleValidator, cfg.Validators)
+}
+
+// checkValidator читает состояние валидатора; ValidatorRejection — если delegate к нему откатится.
+func (s *staker) checkValidator(ctx context.Context, cfg RunParams, id uint64) error {
+	validator, err := s.monadClient.GetValidator(ctx, cfg.ContractAddress, id)
+	if err != nil {
+		return fmt.Errorf("failed to check validator %d: %w", id, err)
+	}
+
+	switch {
+	case validator.AuthAddress == (common.Address{}):
+		return ValidatorRejection{ValidatorID: id, Reason: RejectNotRegistered}
+	case validator.Flags&client.ValidatorFlagDoubleSign != 0:
+		return ValidatorRejection{ValidatorID: id, Reason: RejectJailed, Flags: validator.Flags}
+	case validator.Flags != 0:
+		return ValidatorRejection{ValidatorID: id, Reason: RejectInactive, Flags: validator.Flags}
+	}
+
+	return nil
+}
+
+// reject добавляет валидатора в список отклоненных; предупреждение печатается один раз за запуск.
+func (s *staker) reject(r ValidatorRejection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rejected[r.ValidatorID]; ok {
+		return
+	}
+	s.rejected[r.ValidatorID] = r
+
+	log.Printf("[WARN] %v, excluding it for the rest of the run", r)
+}
+
+func (s *staker) withoutRejected(ids []uint64) []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var left []uint64
+	for _, id := range ids {
+		if _, ok := s.rejected[id]; !ok {
+			left = append(left, id)
+		}
+	}
+
+	return left
+}
+
+// RejectedValidators возвращает валидаторов, отклоненных в этом запуске.
+func (s *staker) RejectedValidators() []ValidatorRejection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]ValidatorRejection, 0, len(s.rejected))
+	for _, r := range s.rejected {
+		list = append(list, r)
+	}
+
+	return list
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"ms/internal/client"
+	"ms/internal/models"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// listSelector выбирает первого кандидата и запоминает, из каких списков выбирал.
+type listSelector struct {
+	seen [][]uint64
+}
+
+func (l *listSelector) Select(_ context.Context, _ models.Account, candidates []uint64) (uint64, error) {
+	l.seen = append(l.seen, append([]uint64(nil), candidates...))
+	return candidates[0], nil
+}
+
+func TestPickValidator(t *testing.T) {
+	active := client.Validator{AuthAddress: common.HexToAddress("0xa")}
+	validators := map[uint64]client.Validator{
+		1: {}, // не зарегистрирован
+		2: {AuthAddress: active.AuthAddress, Flags: client.ValidatorFlagDoubleSign},
+		3: {AuthAddress: active.AuthAddress, Flags: client.ValidatorFlagStakeTooLow},
+		4: active,
+		5: active,
+	}
+
+	tests := []struct {
+		name         string
+		candidates   []uint64
+		want         uint64
+		wantRejected map[uint64]RejectReason
+		wantSelects  int
+	}{
+		{"eligible first pick", []uint64{4, 1}, 4, nil, 1},
+		{
+			name:         "re-pick after rejections",
+			candidates:   []uint64{1, 2, 3, 5},
+			want:         5,
+			wantRejected: map[uint64]RejectReason{1: RejectNotRegistered, 2: RejectJailed, 3: RejectInactive},
+			wantSelects:  4,
+		},
+		{
+			name:         "all candidates rejected",
+			candidates:   []uint64{1, 2, 3},
+			wantRejected: map[uint64]RejectReason{1: RejectNotRegistered, 2: RejectJailed, 3: RejectInactive},
+			wantSelects:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStaker(context.Background(), &fakeClient{validators: validators}, nil)
+			selector := &listSelector{}
+
+			id, err := s.pickValidator(context.Background(), RunParams{Validators: tt.candidates}, selector, models.Account{})
+			if tt.want == 0 {
+				if !errors.Is(err, errNoEligibleValidator) {
+					t.Fatalf("pickValidator = %d, %v; want errNoEligibleValidator", id, err)
+				}
+			} else if err != nil || id != tt.want {
+				t.Fatalf("pickValidator = %d, %v; want %d", id, err, tt.want)
+			}
+
+			if len(selector.seen) != tt.wantSelects {
+				t.Errorf("selector called %d times, want %d: %v", len(selector.seen), tt.wantSelects, selector.seen)
+			}
+			// Отклоненный валидатор больше не предлагается селектору
+			for i := 1; i < len(selector.seen); i++ {
+				rejected := selector.seen[i-1][0]
+				if slices.Contains(selector.seen[i], rejected) {
+					t.Errorf("rejected validator %d offered again in %v", rejected, selector.seen[i])
+				}
+			}
+
+			rejected := s.RejectedValidators()
+			if len(rejected) != len(tt.wantRejected) {
+				t.Fatalf("rejected %v, want %v", rejected, tt.wantRejected)
+			}
+			for _, r := range rejected {
+				if r.Reason != tt.wantRejected[r.ValidatorID] {
+					t.Errorf("validator %d rejected as %q, want %q", r.ValidatorID, r.Reason, tt.wantRejected[r.ValidatorID])
+				}
+			}
+		})
+	}
+}
+
+func TestPickValidatorRemembersRejections(t *testing.T) {
+	fc := &fakeClient{validators: map[uint64]client.Validator{
+		2: {AuthAddress: common.HexToAddress("0xa")},
+	}}
+	s := NewStaker(context.Background(), fc, nil)
+	cfg := RunParams{Validators: []uint64{1, 2}}
+
+	for range 3 {
+		if id, err := s.pickValidator(context.Background(), cfg, &listSelector{}, models.Account{}); err != nil || id != 2 {
+			t.Fatalf("pickValidator = %d, %v; want 2", id, err)
+		}
+	}
+
+	// Валидатор 1 проверен один раз, дальше исключен без запросов к контракту
+	if n := fc.validatorReads[1]; n != 1 {
+		t.Errorf("rejected validator read %d times, want 1", n)
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"ms/internal/client"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeClient — Client с заданными балансом, комиссией и валидаторами; остальные методы не реализованы
+// и паникуют при вызове.
+type fakeClient struct {
+	Client
+
+	balance *big.Int
+	fee     *big.Int
+
+	mu             sync.Mutex
+	validators     map[uint64]client.Validator
+	validatorReads map[uint64]int
+}
+
+func (f *fakeClient) BalanceCheck(context.Context, common.Address) (*big.Int, error) {
+	return new(big.Int).Set(f.balance), nil
+}
+
+func (f *fakeClient) EstimateDelegateFee(context.Context, *big.Int, string, common.Address, uint64) (*big.Int, error) {
+	return new(big.Int).Set(f.fee), nil
+}
+
+// GetValidator возвращает пустого (незарегистрированного) валидатора, если его нет в validators.
+func (f *fakeClient) GetValidator(_ context.Context, _ string, id uint64) (client.Validator, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.validatorReads == nil {
+		f.validatorReads = make(map[uint64]int)
+	}
+	f.validatorReads[id]++
+
+	return f.validators[id], nil
+}
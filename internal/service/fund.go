@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"ms/internal/client"
+	"ms/internal/models"
+	"ms/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type fundTransfer struct {
+	account models.Account
+	amount  *big.Int
+}
+
+// Fund пополняет аккаунты с казначейского адреса: каждому переводится недостающая до TargetBalance
+// сумма или фиксированная Amount. Переводы подписываются подряд с последовательными nonce казначейства,
+// не больше MaxInFlight в полете; общий расход (суммы и максимальные комиссии) ограничен SpendCap.
+func (s *staker) Fund(ctx context.Context, cfg RunParams, fund FundParams, treasury client.Signer, accounts []models.Account) {
+	transfers, total := s.planFunding(ctx, fund, treasury, cfg.accountsForRun(accounts))
+	if len(transfers) == 0 {
+		log.Printf("[INFO] Nothing to fund: all accounts already have the target balance")
+		return
+	}
+
+	log.Printf("[INFO] Funding %d accounts from treasury %s: %s MON in total, spend cap %s MON",
+		len(transfers), treasury.Address().Hex(), utils.FormatEther(total), fund.SpendCap)
+	if cfg.DryRun {
+		log.Printf("[INFO] Dry-run mode: transfers are signed and never broadcast")
+	}
+
+	var (
+		spent    = new(big.Int)
+		sent     int
+		inflight = make(chan struct{}, fund.MaxInFlight)
+		// Подписанные в dry-run переводы: их nonce возвращаются после планирования
+		planned []*types.Transaction
+		// Аккаунты, чей перевод не уложился в остаток SpendCap
+		capped int
+	)
+
+loop:
+	for i, t := range transfers {
+		if !cfg.DryRun {
+			select {
+			case inflight <- struct{}{}:
+			case <-ctx.Done():
+				log.Printf("[INFO] Context cancelled, stopping at transfer %d/%d", i, len(transfers))
+				break loop
+			}
+		}
+		done := func() {
+			if !cfg.DryRun {
+				<-inflight
+			}
+		}
+
+		signedTx, plan, err := s.monadClient.SignTransfer(ctx, t.amount, t.account.Address, treasury)
+		if err != nil {
+			done()
+			log.Printf("[WARN] failed to fund %s: %v", t.account, err)
+			if errors.Is(err, client.ErrInsufficientBalance) || ctx.Err() != nil {
+				log.Printf("[WARN] stopping: %d accounts left unfunded", len(transfers)-i)
+				break
+			}
+			continue
+		}
+
+		// Перевод сверх остатка пропускается, но меньшие переводы следующим аккаунтам еще могут уложиться
+		cost := new(big.Int).Add(plan.Amount, plan.EstimatedCost)
+		if new(big.Int).Add(spent, cost).Cmp(fund.SpendCap.Wei()) > 0 {
+			s.monadClient.ReleaseTransaction(signedTx)
+			done()
+			capped++
+			log.Printf("[WARN] skipping %s: %s MON with max fee exceeds spend cap %s MON (%s MON spent)",
+				t.account, utils.FormatEther(cost), fund.SpendCap, utils.FormatEther(spent))
+			continue
+		}
+
+		if cfg.DryRun {
+			spent.Add(spent, cost)
+			planned = append(planned, signedTx)
+			log.Printf("[INFO] [DRY-RUN] %s: transfer %s MON | nonce %d | gas limit %d | max fee %s gwei | est. cost %s MON",
+				t.account, utils.FormatEther(plan.Amount), plan.Nonce, plan.GasLimit,
+				utils.FormatUnits(plan.MaxFeePerGas, gweiDecimals), utils.FormatEther(plan.EstimatedCost))
+			continue
+		}
+
+		if err := s.monadClient.BroadcastTransaction(ctx, signedTx); err != nil {
+			if !errors.Is(err, client.ErrSendUnconfirmed) {
+				done()
+				log.Printf("[WARN] failed to fund %s: %v", t.account, err)
+				continue
+			}
+			// Перевод мог дойти до сети и занимает nonce казначейства — ждем его как отправленный
+			log.Printf("[WARN] funding %s: %v, waiting for it as sent", t.account, err)
+		}
+		spent.Add(spent, cost)
+		sent++
+
+		s.wg.Add(1)
+		go func(t fundTransfer, signedTx *types.Transaction) {
+			defer s.wg.Done()
+			defer done()
+
+			if _, err := s.monadClient.WaitForTransaction(ctx, signedTx, treasury, nil); err != nil {
+				log.Printf("[WARN] failed to fund %s: %v", t.account, err)
+				return
+			}
+
+			log.Printf("[INFO] funded %s with %s MON", t.account, utils.FormatEther(t.amount))
+		}(t, signedTx)
+	}
+
+	if capped > 0 {
+		log.Printf("[WARN] spend cap %s MON: %d accounts left unfunded", fund.SpendCap, capped)
+	}
+
+	// Nonce возвращаются с конца, чтобы менеджер откатился к первому без пересинхронизации
+	for i := len(planned) - 1; i >= 0; i-- {
+		s.monadClient.ReleaseTransaction(planned[i])
+	}
+
+	if cfg.DryRun {
+		log.Printf("[INFO] [DRY-RUN] %d transfers planned, up to %s MON including max fees", len(planned), utils.FormatEther(spent))
+		return
+	}
+
+	log.Printf("[INFO] %d transfers sent, up to %s MON including max fees", sent, utils.FormatEther(spent))
+}
+
+// planFunding считает недостающую сумму каждого аккаунта. Сам адрес казначейства пропускается.
+func (s *staker) planFunding(ctx context.Context, fund FundParams, treasury client.Signer, accounts []models.Account) ([]fundTransfer, *big.Int) {
+	var (
+		transfers []fundTransfer
+		total     = new(big.Int)
+	)
+
+	for _, acc := range accounts {
+		if acc.Address == treasury.Address() {
+			continue
+		}
+
+		amount := fund.Amount.Wei()
+		if amount.Sign() == 0 {
+			balance, err := s.monadClient.BalanceCheck(ctx, acc.Address)
+			if err != nil {
+				log.Printf("[WARN] skipping %s: %v", acc, err)
+				continue
+			}
+
+			amount.Sub(fund.TargetBalance.Wei(), balance)
+			if amount.Sign() <= 0 {
+				continue
+			}
+		}
+
+		transfers = append(transfers, fundTransfer{account: acc, amount: amount})
+		total.Add(total, amount)
+	}
+
+	return transfers, total
+}
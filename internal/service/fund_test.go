@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"ms/internal/client"
+	"ms/internal/models"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newTreasury(t *testing.T) client.Signer {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client.NewKeySigner(key)
+}
+
+// transferClient подписывает переводы казначейства с последовательными nonce и запоминает отправленные.
+type transferClient struct {
+	Client
+
+	balances map[common.Address]*big.Int
+	fee      *big.Int
+
+	mu    sync.Mutex
+	nonce uint64
+	sent  map[common.Address]*big.Int
+}
+
+func (c *transferClient) BalanceCheck(_ context.Context, addr common.Address) (*big.Int, error) {
+	return new(big.Int).Set(c.balances[addr]), nil
+}
+
+func (c *transferClient) SignTransfer(_ context.Context, amount *big.Int, to common.Address, _ client.Signer) (*types.Transaction, client.TxPlan, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: c.nonce, To: &to, Value: amount, Gas: 21000})
+	c.nonce++
+
+	return tx, client.TxPlan{Amount: amount, Nonce: tx.Nonce(), EstimatedCost: c.fee}, nil
+}
+
+func (c *transferClient) ReleaseTransaction(tx *types.Transaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if tx.Nonce() == c.nonce-1 {
+		c.nonce--
+	}
+}
+
+func (c *transferClient) BroadcastTransaction(_ context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sent[*tx.To()] = tx.Value()
+	return nil
+}
+
+func (c *transferClient) WaitForTransaction(_ context.Context, tx *types.Transaction, _ client.Signer, _ func(*types.Transaction)) (*types.Transaction, error) {
+	return tx, nil
+}
+
+func TestFundSkipsTransfersAboveSpendCap(t *testing.T) {
+	accounts := make([]models.Account, 4)
+	for i := range accounts {
+		accounts[i] = models.Account{Address: common.BigToAddress(big.NewInt(int64(i + 1))), Index: uint32(i)}
+	}
+
+	// До 10 MON не хватает 2, 9, 3 и 1 MON; с комиссией 0.5 в предел 7.5 укладываются 2.5 + 3.5 + 1.5
+	fc := &transferClient{
+		balances: map[common.Address]*big.Int{
+			accounts[0].Address: mon("8").Wei(),
+			accounts[1].Address: mon("1").Wei(),
+			accounts[2].Address: mon("7").Wei(),
+			accounts[3].Address: mon("9").Wei(),
+		},
+		fee:  mon("0.5").Wei(),
+		sent: make(map[common.Address]*big.Int),
+	}
+	s := &staker{monadClient: fc}
+
+	fund := FundParams{TargetBalance: mon("10"), SpendCap: mon("7.5"), MaxInFlight: 2}
+	s.Fund(context.Background(), RunParams{}, fund, newTreasury(t), accounts)
+	s.wg.Wait()
+
+	want := map[common.Address]string{
+		accounts[0].Address: "2",
+		accounts[2].Address: "3",
+		accounts[3].Address: "1",
+	}
+	if len(fc.sent) != len(want) {
+		t.Fatalf("sent %d transfers, want %d: %v", len(fc.sent), len(want), fc.sent)
+	}
+	for addr, amount := range want {
+		if got := fc.sent[addr]; got == nil || got.Cmp(mon(amount).Wei()) != 0 {
+			t.Errorf("transfer to %s = %v, want %s MON", addr.Hex(), got, amount)
+		}
+	}
+
+	// Nonce пропущенного перевода занят следующим
+	if fc.nonce != 3 {
+		t.Errorf("treasury nonce %d after 3 transfers, want 3", fc.nonce)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"log"
+	"ms/internal/models"
+	"strings"
+)
+
+// forAccount возвращает параметры запуска аккаунта: общие RunParams с его переопределениями.
+func (p RunParams) forAccount(acc models.Account) RunParams {
+	o, ok := p.override(acc)
+	if !ok {
+		return p
+	}
+
+	if o.Stake != nil {
+		p.Stake = *o.Stake
+	}
+	if o.Delay != nil {
+		p.Delay = *o.Delay
+	}
+	if len(o.Validators) > 0 {
+		p.Validators = o.Validators
+	}
+
+	return p
+}
+
+// withDelay возвращает параметры с общей задержкой delay: Delay и задержки из настроек аккаунтов
+// относятся к стейкингу, а у sweep своя задержка.
+func (p RunParams) withDelay(delay Range) RunParams {
+	p.Delay = delay
+
+	overrides := make(map[string]AccountOverride, len(p.Overrides))
+	for key, o := range p.Overrides {
+		o.Delay = nil
+		overrides[key] = o
+	}
+	p.Overrides = overrides
+
+	return p
+}
+
+// override ищет настройки аккаунта сначала по адресу, затем по метке.
+func (p RunParams) override(acc models.Account) (AccountOverride, bool) {
+	if o, ok := p.Overrides[strings.ToLower(acc.Address.Hex())]; ok {
+		return o, true
+	}
+
+	if acc.Label != "" {
+		o, ok := p.Overrides[strings.ToLower(acc.Label)]
+		return o, ok
+	}
+
+	return AccountOverride{}, false
+}
+
+// accountsForRun отбрасывает аккаунты, выключенные в файле аккаунтов, и предупреждает
+// о настройках, которые не подошли ни к одному загруженному аккаунту.
+func (p RunParams) accountsForRun(accounts []models.Account) []models.Account {
+	if len(p.Overrides) == 0 {
+		return accounts
+	}
+
+	matched := make(map[string]struct{}, len(p.Overrides))
+	enabled := make([]models.Account, 0, len(accounts))
+	for _, acc := range accounts {
+		for _, key := range []string{strings.ToLower(acc.Address.Hex()), strings.ToLower(acc.Label)} {
+			if _, ok := p.Overrides[key]; ok {
+				matched[key] = struct{}{}
+			}
+		}
+
+		if o, ok := p.override(acc); ok && o.Enabled != nil && !*o.Enabled {
+			log.Printf("[INFO] %s is disabled in the accounts file, skipping", acc)
+			continue
+		}
+
+		enabled = append(enabled, acc)
+	}
+
+	for key := range p.Overrides {
+		if _, ok := matched[key]; !ok {
+			log.Printf("[WARN] accounts file entry %q matches no loaded account", key)
+		}
+	}
+
+	return enabled
+}
+
+// allValidators возвращает общие валидаторы вместе с валидаторами из настроек аккаунтов.
+func (p RunParams) allValidators() []uint64 {
+	seen := make(map[uint64]struct{})
+	var ids []uint64
+	add := func(list []uint64) {
+		for _, id := range list {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	add(p.Validators)
+	for _, o := range p.Overrides {
+		add(o.Validators)
+	}
+
+	return ids
+}
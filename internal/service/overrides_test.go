@@ -0,0 +1,33 @@
+package service
+
+import (
+	"ms/internal/models"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestWithDelay(t *testing.T) {
+	acc := models.Account{Address: common.HexToAddress("0x1"), Label: "alice"}
+	enabled := false
+	cfg := RunParams{
+		Delay: Range{Min: 30, Max: 60},
+		Overrides: map[string]AccountOverride{
+			"alice": {Delay: &Range{Min: 100, Max: 200}, Enabled: &enabled},
+		},
+	}
+
+	sweepCfg := cfg.withDelay(Range{Min: 1, Max: 3})
+
+	if got := sweepCfg.forAccount(acc).Delay; got != (Range{Min: 1, Max: 3}) {
+		t.Errorf("sweep delay for %s = %+v, want 1..3", acc, got)
+	}
+	if o := sweepCfg.Overrides["alice"]; o.Enabled == nil || *o.Enabled {
+		t.Errorf("override lost enabled: %+v", o)
+	}
+
+	// Исходные параметры не меняются
+	if got := cfg.forAccount(acc).Delay; got != (Range{Min: 100, Max: 200}) {
+		t.Errorf("stake delay for %s = %+v, want 100..200", acc, got)
+	}
+}
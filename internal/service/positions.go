@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"ms/internal/client"
+	consts "ms/internal/client/consts"
+	"ms/internal/models"
+	"ms/pkg/utils"
+	"strings"
+	"text/tabwriter"
+)
+
+// Positions печатает в out стейк, невыведенные награды и ожидающие withdraw
+// заявки каждого аккаунта по всем валидаторам, которым он делегировал.
+func (s *staker) Positions(ctx context.Context, cfg RunParams, accounts []models.Account, out io.Writer) error {
+	epoch, err := s.monadClient.GetEpoch(ctx, cfg.ContractAddress)
+	if err != nil {
+		return fmt.Errorf("failed to get current epoch: %w", err)
+	}
+
+	fmt.Fprintf(out, "Current epoch: %d\n\n", epoch.Number)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tACCOUNT\tVALIDATOR\tSTAKE (MON)\tREWARDS (MON)\tPENDING WITHDRAWALS")
+
+	totalStake, totalRewards := new(big.Int), new(big.Int)
+	for _, acc := range accounts {
+		select {
+		case <-ctx.Done():
+			tw.Flush()
+			return ctx.Err()
+		default:
+		}
+
+		validators, err := s.monadClient.GetDelegations(ctx, cfg.ContractAddress, acc.Address)
+		if err != nil {
+			log.Printf("[WARN] failed to read delegations of %s: %v", acc, err)
+			continue
+		}
+
+		if len(validators) == 0 {
+			fmt.Fprintf(tw, "%d\t%s\t-\t0\t0\t-\n", acc.Index, acc.Address.Hex())
+			continue
+		}
+
+		for _, validatorID := range validators {
+			delegator, err := s.monadClient.GetDelegator(ctx, cfg.ContractAddress, validatorID, acc.Address)
+			if err != nil {
+				log.Printf("[WARN] failed to read delegation of %s (validator: %d): %v", acc, validatorID, err)
+				continue
+			}
+
+			totalStake.Add(totalStake, delegator.Stake)
+			totalRewards.Add(totalRewards, delegator.UnclaimedRewards)
+
+			fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\t%s\n",
+				acc.Index,
+				acc.Address.Hex(),
+				validatorID,
+				utils.FormatEther(delegator.Stake),
+				utils.FormatEther(delegator.UnclaimedRewards),
+				s.describeWithdrawals(ctx, cfg, acc, validatorID, epoch.Number),
+			)
+		}
+	}
+
+	fmt.Fprintf(tw, "TOTAL\t\t\t%s\t%s\t\n", utils.FormatEther(totalStake), utils.FormatEther(totalRewards))
+
+	return tw.Flush()
+}
+
+func (s *staker) describeWithdrawals(ctx context.Context, cfg RunParams, acc models.Account, validatorID uint64, currentEpoch uint64) string {
+	slots, _, err := scanWithdrawSlots(func(withdrawID uint8) (client.WithdrawalRequest, error) {
+		return s.monadClient.GetWithdrawalRequest(ctx, cfg.ContractAddress, validatorID, acc.Address, withdrawID)
+	})
+	if err != nil {
+		log.Printf("[WARN] failed to read withdrawal request of %s: %v", acc, err)
+		return "?"
+	}
+
+	var parts []string
+	for _, slot := range slots {
+		id, req := slot.id, slot.req
+
+		status := "ready"
+		if currentEpoch < req.WithdrawEpoch+consts.WithdrawalDelay {
+			status = fmt.Sprintf("epoch %d", req.WithdrawEpoch+consts.WithdrawalDelay)
+		}
+
+		parts = append(parts, fmt.Sprintf("#%d: %s MON (%s)", id, utils.FormatEther(req.WithdrawalAmount), status))
+	}
+
+	if len(parts) == 0 {
+		return "-"
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"log"
+	"ms/internal/client"
+	"ms/internal/journal"
+	"ms/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// resumeAccounts отбрасывает аккаунты, уже застейканные в текущем запуске журнала, и досверяет
+// транзакции, оставшиеся в полете после прерванного запуска.
+func (s *staker) resumeAccounts(ctx context.Context, accounts []models.Account) []models.Account {
+	if s.journal == nil {
+		return accounts
+	}
+
+	var (
+		remaining []models.Account
+		completed int
+	)
+
+	for _, acc := range accounts {
+		entry, ok := s.journal.Last(acc.Address)
+		if !ok {
+			remaining = append(remaining, acc)
+			continue
+		}
+
+		switch entry.Status {
+		case journal.StatusSuccess:
+			completed++
+		case journal.StatusFailed, journal.StatusSkipped:
+			remaining = append(remaining, acc)
+		case journal.StatusSigned, journal.StatusSent:
+			if s.recheckInFlight(ctx, acc, entry) {
+				remaining = append(remaining, acc)
+			} else {
+				completed++
+			}
+		}
+	}
+
+	if completed > 0 {
+		log.Printf("[INFO] Resuming run %s: %d accounts already completed, %d left", s.journal.RunID(), completed, len(remaining))
+	}
+
+	return remaining
+}
+
+// recheckInFlight проверяет транзакцию из журнала и возвращает true, если аккаунт нужно стейкать заново.
+func (s *staker) recheckInFlight(ctx context.Context, acc models.Account, entry journal.Entry) bool {
+	mined, success, err := s.monadClient.TransactionReceiptStatus(ctx, common.HexToHash(entry.TxHash))
+	if err != nil {
+		log.Printf("[WARN] failed to re-check %s for %s, skipping account: %v", entry.TxHash, acc, err)
+		return false
+	}
+
+	if mined {
+		if success {
+			log.Printf("[INFO] in-flight stake %s for %s was mined successfully", entry.TxHash, acc)
+			s.record(entry, journal.StatusSuccess, nil)
+			return false
+		}
+
+		log.Printf("[WARN] in-flight stake %s for %s reverted, staking again", entry.TxHash, acc)
+		s.record(entry, journal.StatusFailed, client.ErrTxReverted)
+		return true
+	}
+
+	nonce, err := s.monadClient.GetNonce(ctx, acc.Address)
+	if err != nil {
+		log.Printf("[WARN] failed to re-check %s for %s, skipping account: %v", entry.TxHash, acc, err)
+		return false
+	}
+
+	// Если nonce транзакции так и не занят, она не дошла до сети и ее можно переподписать
+	if nonce <= entry.Nonce {
+		log.Printf("[WARN] in-flight stake %s for %s was never mined, staking again", entry.TxHash, acc)
+		return true
+	}
+
+	log.Printf("[WARN] stake %s for %s is still pending or was replaced, skipping account", entry.TxHash, acc)
+	return false
+}
+
+func (s *staker) record(entry journal.Entry, status journal.Status, err error) {
+	entry.Status = status
+	entry.Error = ""
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if err := s.journal.Record(entry); err != nil {
+		log.Printf("[WARN] failed to write journal: %v", err)
+	}
+}
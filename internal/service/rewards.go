@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"log"
+	"ms/internal/client"
+	"ms/internal/models"
+	"ms/pkg/utils"
+)
+
+type rewardAction struct {
+	name string
+	send func(ctx context.Context, to string, signer client.Signer, validatorID uint64) error
+}
+
+// Compound реинвестирует накопленные награды каждого аккаунта у всех валидаторов, которым он делегировал.
+func (s *staker) Compound(ctx context.Context, cfg RunParams, accounts []models.Account) {
+	log.Printf("[INFO] Starting $MON compound process for %d accounts...", len(accounts))
+
+	s.processRewards(ctx, cfg, accounts, rewardAction{name: "compound", send: s.monadClient.Compound})
+}
+
+// ClaimRewards выводит накопленные награды каждого аккаунта у всех валидаторов, которым он делегировал.
+func (s *staker) ClaimRewards(ctx context.Context, cfg RunParams, accounts []models.Account) {
+	log.Printf("[INFO] Starting $MON claim rewards process for %d accounts...", len(accounts))
+
+	s.processRewards(ctx, cfg, accounts, rewardAction{name: "claim rewards", send: s.monadClient.ClaimRewards})
+}
+
+func (s *staker) processRewards(ctx context.Context, cfg RunParams, accounts []models.Account, action rewardAction) {
+	s.forEachAccount(ctx, cfg, accounts, func(acc models.Account) {
+		validators, err := s.monadClient.GetDelegations(ctx, cfg.ContractAddress, acc.Address)
+		if err != nil {
+			log.Printf("[WARN] failed to read delegations of %s: %v", acc, err)
+			return
+		}
+
+		for _, validatorID := range validators {
+			select {
+			case <-ctx.Done():
+				log.Printf("[INFO] Context cancelled, skipping %s for %s", action.name, acc)
+				return
+			default:
+			}
+
+			delegator, err := s.monadClient.GetDelegator(ctx, cfg.ContractAddress, validatorID, acc.Address)
+			if err != nil {
+				log.Printf("[WARN] failed to read delegation of %s (validator: %d): %v", acc, validatorID, err)
+				continue
+			}
+
+			if delegator.UnclaimedRewards.Sign() == 0 {
+				continue
+			}
+
+			if err := action.send(ctx, cfg.ContractAddress, acc.Signer, validatorID); err != nil {
+				log.Printf("[WARN] failed %s for %s (validator: %d): %v", action.name, acc, validatorID, err)
+				continue
+			}
+
+			log.Printf("[INFO] successfully %s: %s MON for %s (validator: %d)", action.name, utils.FormatEther(delegator.UnclaimedRewards), acc, validatorID)
+		}
+	})
+}
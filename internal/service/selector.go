@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"ms/internal/client"
+	"ms/internal/models"
+	"ms/pkg/utils"
+	"sync"
+)
+
+const (
+	SelectRandom           SelectionStrategy = "random"
+	SelectRoundRobin       SelectionStrategy = "roundRobin"
+	SelectWeighted         SelectionStrategy = "weighted"
+	SelectLowestCommission SelectionStrategy = "lowestCommission"
+	SelectLeastDelegated   SelectionStrategy = "leastDelegated"
+	SelectStakeWeighted    SelectionStrategy = "stakeWeighted"
+)
+
+// ValidatorSelector выбирает валидатора для аккаунта из списка кандидатов (общего или из настроек аккаунта).
+// Реализации безопасны для вызова из горутин forEachAccount.
+type ValidatorSelector interface {
+	Select(ctx context.Context, acc models.Account, candidates []uint64) (uint64, error)
+}
+
+// newValidatorSelector создает селектор стратегии cfg.Selection. Для leastDelegated сразу читаются
+// текущие делегирования всех аккаунтов запуска.
+func (s *staker) newValidatorSelector(ctx context.Context, cfg RunParams, accounts []models.Account) (ValidatorSelector, error) {
+	switch cfg.Selection.Strategy {
+	case SelectRandom, "":
+		return randomSelector{}, nil
+	case SelectRoundRobin:
+		return &roundRobinSelector{}, nil
+	case SelectWeighted:
+		return weightedSelector{weights: cfg.Selection.Weights}, nil
+	case SelectLowestCommission:
+		return &validatorInfoSelector{client: s.monadClient, contract: cfg.ContractAddress, pick: lowestCommission}, nil
+	case SelectStakeWeighted:
+		return &validatorInfoSelector{client: s.monadClient, contract: cfg.ContractAddress, pick: stakeWeighted}, nil
+	case SelectLeastDelegated:
+		return s.newLeastDelegatedSelector(ctx, cfg, accounts)
+	default:
+		return nil, fmt.Errorf("unknown validator selection strategy %q", cfg.Selection.Strategy)
+	}
+}
+
+// randomSelector — равновероятный выбор, как было до появления стратегий.
+type randomSelector struct{}
+
+func (randomSelector) Select(_ context.Context, _ models.Account, candidates []uint64) (uint64, error) {
+	return utils.RandomSliceValue(candidates), nil
+}
+
+// roundRobinSelector выдает кандидатов по кругу в порядке списка.
+type roundRobinSelector struct {
+	mu   sync.Mutex
+	next int
+}
+
+func (r *roundRobinSelector) Select(_ context.Context, _ models.Account, candidates []uint64) (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := candidates[r.next%len(candidates)]
+	r.next++
+
+	return id, nil
+}
+
+// weightedSelector выбирает случайно пропорционально весам из конфига; валидатор без веса имеет вес 1.
+type weightedSelector struct {
+	weights map[uint64]uint64
+}
+
+func (w weightedSelector) Select(_ context.Context, _ models.Account, candidates []uint64) (uint64, error) {
+	weights := make([]*big.Int, len(candidates))
+	for i, id := range candidates {
+		weight, ok := w.weights[id]
+		if !ok {
+			weight = 1
+		}
+		weights[i] = new(big.Int).SetUint64(weight)
+	}
+
+	return pickWeighted(candidates, weights)
+}
+
+// leastDelegatedSelector выбирает валидатора, которому делегировало меньше всего наших аккаунтов,
+// и сразу засчитывает ему выбор, чтобы следующие аккаунты распределялись равномерно.
+type leastDelegatedSelector struct {
+	mu     sync.Mutex
+	counts map[uint64]int
+}
+
+func (s *staker) newLeastDelegatedSelector(ctx context.Context, cfg RunParams, accounts []models.Account) (*leastDelegatedSelector, error) {
+	counts := make(map[uint64]int)
+	for _, acc := range accounts {
+		validators, err := s.monadClient.GetDelegations(ctx, cfg.ContractAddress, acc.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read delegations of %s: %w", acc, err)
+		}
+
+		for _, id := range validators {
+			counts[id]++
+		}
+	}
+
+	return &leastDelegatedSelector{counts: counts}, nil
+}
+
+func (l *leastDelegatedSelector) Select(_ context.Context, _ models.Account, candidates []uint64) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	best := candidates[0]
+	for _, id := range candidates[1:] {
+		if l.counts[id] < l.counts[best] {
+			best = id
+		}
+	}
+	l.counts[best]++
+
+	return best, nil
+}
+
+// validatorInfoSelector выбирает по данным getValidator стейкинг-контракта. Данные читаются
+// один раз за запуск: комиссия и стейк валидатора за время запуска меняются мало.
+type validatorInfoSelector struct {
+	client   Client
+	contract string
+	pick     func(candidates []uint64, info []client.Validator) (uint64, error)
+
+	mu    sync.Mutex
+	cache map[uint64]client.Validator
+}
+
+func (v *validatorInfoSelector) Select(ctx context.Context, _ models.Account, candidates []uint64) (uint64, error) {
+	info := make([]client.Validator, len(candidates))
+	for i, id := range candidates {
+		validator, err := v.validator(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		info[i] = validator
+	}
+
+	return v.pick(candidates, info)
+}
+
+func (v *validatorInfoSelector) validator(ctx context.Context, id uint64) (client.Validator, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if validator, ok := v.cache[id]; ok {
+		return validator, nil
+	}
+
+	validator, err := v.client.GetValidator(ctx, v.contract, id)
+	if err != nil {
+		return client.Validator{}, fmt.Errorf("failed to read validator %d: %w", id, err)
+	}
+
+	if v.cache == nil {
+		v.cache = make(map[uint64]client.Validator)
+	}
+	v.cache[id] = validator
+
+	return validator, nil
+}
+
+// lowestCommission выбирает валидатора с наименьшей комиссией; при равенстве — первого в списке.
+func lowestCommission(candidates []uint64, info []client.Validator) (uint64, error) {
+	best := 0
+	for i := range candidates[1:] {
+		if info[i+1].Commission.Cmp(info[best].Commission) < 0 {
+			best = i + 1
+		}
+	}
+
+	return candidates[best], nil
+}
+
+// stakeWeighted выбирает случайно пропорционально стейку валидатора в сети.
+func stakeWeighted(candidates []uint64, info []client.Validator) (uint64, error) {
+	weights := make([]*big.Int, len(candidates))
+	for i := range info {
+		weights[i] = info[i].Stake
+	}
+
+	id, err := pickWeighted(candidates, weights)
+	if err != nil {
+		log.Printf("[WARN] %v, falling back to random validator", err)
+		return utils.RandomSliceValue(candidates), nil
+	}
+
+	return id, nil
+}
+
+// pickWeighted выбирает элемент с вероятностью, пропорциональной его весу.
+func pickWeighted(candidates []uint64, weights []*big.Int) (uint64, error) {
+	total := new(big.Int)
+	for _, w := range weights {
+		total.Add(total, w)
+	}
+	if total.Sign() <= 0 {
+		return 0, errors.New("all candidate validators have zero weight")
+	}
+
+	r, err := rand.Int(rand.Reader, total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to pick validator: %w", err)
+	}
+
+	for i, w := range weights {
+		if r.Cmp(w) < 0 {
+			return candidates[i], nil
+		}
+		r.Sub(r, w)
+	}
+
+	return candidates[len(candidates)-1], nil
+}
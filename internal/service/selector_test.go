@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"ms/internal/client"
+	"ms/internal/models"
+	"testing"
+)
+
+func TestPickWeighted(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []uint64
+		weights    []int64
+		// Доля выборов каждого кандидата, ожидаемая при весах
+		want    []float64
+		wantErr bool
+	}{
+		{"single non-zero weight", []uint64{1, 2, 3}, []int64{0, 5, 0}, []float64{0, 1, 0}, false},
+		{"proportional", []uint64{1, 2}, []int64{1, 3}, []float64{0.25, 0.75}, false},
+		{"equal", []uint64{7, 8, 9}, []int64{2, 2, 2}, []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}, false},
+		{"all zero", []uint64{1, 2}, []int64{0, 0}, nil, true},
+	}
+
+	const draws = 20000
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights := make([]*big.Int, len(tt.weights))
+			for i, w := range tt.weights {
+				weights[i] = big.NewInt(w)
+			}
+
+			if tt.wantErr {
+				if id, err := pickWeighted(tt.candidates, weights); err == nil {
+					t.Fatalf("pickWeighted = %d, want error", id)
+				}
+				return
+			}
+
+			counts := make(map[uint64]int)
+			for range draws {
+				id, err := pickWeighted(tt.candidates, weights)
+				if err != nil {
+					t.Fatalf("pickWeighted: %v", err)
+				}
+				counts[id]++
+			}
+
+			for i, id := range tt.candidates {
+				got := float64(counts[id]) / draws
+				if tt.want[i] == 0 && counts[id] > 0 {
+					t.Errorf("validator %d with zero weight picked %d times", id, counts[id])
+				}
+				// 20000 выборов: отклонение больше 3% практически невозможно
+				if diff := got - tt.want[i]; diff > 0.03 || diff < -0.03 {
+					t.Errorf("validator %d picked %.3f of draws, want %.3f", id, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLowestCommission(t *testing.T) {
+	tests := []struct {
+		name        string
+		candidates  []uint64
+		commissions []int64
+		want        uint64
+	}{
+		{"lowest wins", []uint64{1, 2, 3}, []int64{10, 5, 7}, 2},
+		{"tie goes to first in list", []uint64{4, 5, 6}, []int64{8, 3, 3}, 5},
+		{"all equal", []uint64{9, 8}, []int64{1, 1}, 9},
+		{"single candidate", []uint64{3}, []int64{50}, 3},
+	}
+
+	for _, tt := range tests {
+		info := make([]client.Validator, len(tt.commissions))
+		for i, c := range tt.commissions {
+			info[i] = client.Validator{Commission: big.NewInt(c)}
+		}
+
+		if got, err := lowestCommission(tt.candidates, info); err != nil || got != tt.want {
+			t.Errorf("%s: lowestCommission = %d, %v; want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestWeightedSelectorDefaultsToOne(t *testing.T) {
+	// Валидатор 2 без веса получает вес 1, валидатор 1 с весом 0 не выбирается никогда
+	w := weightedSelector{weights: map[uint64]uint64{1: 0}}
+	for range 100 {
+		if id, err := w.Select(context.Background(), models.Account{}, []uint64{1, 2}); err != nil || id != 2 {
+			t.Fatalf("Select = %d, %v; want 2", id, err)
+		}
+	}
+}
+
+func TestValidatorInfoSelectorCachesValidators(t *testing.T) {
+	fc := &fakeClient{validators: map[uint64]client.Validator{
+		1: {Commission: big.NewInt(10)},
+		2: {Commission: big.NewInt(5)},
+	}}
+	v := &validatorInfoSelector{client: fc, pick: lowestCommission}
+
+	for range 3 {
+		if id, err := v.Select(context.Background(), models.Account{}, []uint64{1, 2}); err != nil || id != 2 {
+			t.Fatalf("Select = %d, %v; want 2", id, err)
+		}
+	}
+
+	for id, n := range fc.validatorReads {
+		if n != 1 {
+			t.Errorf("validator %d read %d times, want 1", id, n)
+		}
+	}
+}
+
+func TestLeastDelegatedSelector(t *testing.T) {
+	l := &leastDelegatedSelector{counts: map[uint64]int{1: 2, 2: 0, 3: 1}}
+
+	var got []uint64
+	for range 4 {
+		id, err := l.Select(context.Background(), models.Account{}, []uint64{1, 2, 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, id)
+	}
+
+	// Счетчики (2,0,1) → 2; (2,1,1) → 2, при равенстве — первый в списке; (2,2,1) → 3; (2,2,2) → 1
+	want := []uint64{2, 2, 3, 1}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("picks %v, want %v", got, want)
+		}
+	}
+}
@@ -2,33 +2,74 @@ package service
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"log"
+	"math/big"
+	"ms/internal/client"
+	"ms/internal/journal"
 	"ms/internal/models"
 	"ms/pkg/utils"
 	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type (
 	Client interface {
-		SendTransaction(ctx context.Context, amount float32, to string, privatekey *ecdsa.PrivateKey, validatorID uint8) error
+		SignDelegate(ctx context.Context, amount *big.Int, to string, signer client.Signer, validatorID uint64) (*types.Transaction, client.TxPlan, error)
+		BroadcastTransaction(ctx context.Context, signedTx *types.Transaction) error
+		WaitForTransaction(ctx context.Context, signedTx *types.Transaction, signer client.Signer, onReplace func(*types.Transaction)) (*types.Transaction, error)
+		TransactionReceiptStatus(ctx context.Context, txHash common.Hash) (mined bool, success bool, err error)
+		GetNonce(ctx context.Context, address common.Address) (uint64, error)
+		BalanceCheck(ctx context.Context, owner common.Address) (*big.Int, error)
+		EstimateDelegateFee(ctx context.Context, amount *big.Int, to string, from common.Address, validatorID uint64) (*big.Int, error)
+		SignTransfer(ctx context.Context, amount *big.Int, to common.Address, signer client.Signer) (*types.Transaction, client.TxPlan, error)
+		SignSweep(ctx context.Context, to common.Address, signer client.Signer) (*types.Transaction, client.TxPlan, error)
+		ReleaseTransaction(signedTx *types.Transaction)
+		SimulateDelegate(ctx context.Context, amount *big.Int, to string, signer client.Signer, validatorID uint64) (client.TxPlan, error)
+		Undelegate(ctx context.Context, amount *big.Int, to string, signer client.Signer, validatorID uint64, withdrawID uint8) error
+		Withdraw(ctx context.Context, to string, signer client.Signer, validatorID uint64, withdrawID uint8) error
+		Compound(ctx context.Context, to string, signer client.Signer, validatorID uint64) error
+		ClaimRewards(ctx context.Context, to string, signer client.Signer, validatorID uint64) error
+
+		GetDelegator(ctx context.Context, to string, validatorID uint64, delegator common.Address) (client.Delegator, error)
+		GetWithdrawalRequest(ctx context.Context, to string, validatorID uint64, delegator common.Address, withdrawID uint8) (client.WithdrawalRequest, error)
+		GetEpoch(ctx context.Context, to string) (client.Epoch, error)
+		GetValidator(ctx context.Context, to string, validatorID uint64) (client.Validator, error)
+		GetDelegations(ctx context.Context, to string, delegator common.Address) ([]uint64, error)
+		GetValidatorSet(ctx context.Context, to string) ([]uint64, error)
 	}
 )
 
+const gweiDecimals = 9
+
 type staker struct {
 	monadClient Client
 	ctx         context.Context
 	wg          sync.WaitGroup
+
+	// Журнал запуска stake; nil — без журнала (dry-run и остальные режимы).
+	journal *journal.Journal
+
+	mu      sync.Mutex
+	pending map[common.Address][]pendingWithdrawal
+	// Валидаторы, отклоненные в этом запуске (см. pickValidator).
+	rejected map[uint64]ValidatorRejection
 }
 
 func NewStaker(
 	ctx context.Context,
 	monadClient Client,
+	runJournal *journal.Journal,
 ) *staker {
 	return &staker{
 		monadClient: monadClient,
 		ctx:         ctx,
+		journal:     runJournal,
+		pending:     make(map[common.Address][]pendingWithdrawal),
+		rejected:    make(map[uint64]ValidatorRejection),
 	}
 }
 
@@ -38,6 +79,135 @@ func (s *staker) Wait() {
 
 func (s *staker) Start(ctx context.Context, cfg RunParams, accounts []models.Account) {
 	log.Printf("[INFO] Starting $MON staking process for %d accounts...", len(accounts))
+	if cfg.DryRun {
+		log.Printf("[INFO] Dry-run mode: transactions are simulated and never broadcast")
+	} else {
+		accounts = s.resumeAccounts(ctx, accounts)
+		if len(accounts) == 0 {
+			log.Printf("[INFO] All accounts are already completed in run %s, start with --new-run to stake again", s.journal.RunID())
+			return
+		}
+	}
+
+	selector, err := s.newValidatorSelector(ctx, cfg, accounts)
+	if err != nil {
+		log.Printf("[WARN] failed to init validator selection: %v", err)
+		return
+	}
+
+	s.forEachAccount(ctx, cfg, accounts, func(acc models.Account) {
+		params := cfg.forAccount(acc)
+
+		validator, err := s.pickValidator(ctx, params, selector, acc)
+		if err != nil {
+			s.skip(acc, validator, err)
+			return
+		}
+
+		stake, err := s.stakeAmount(ctx, params, acc, validator)
+		if err != nil {
+			s.skip(acc, validator, err)
+			return
+		}
+
+		if cfg.DryRun {
+			s.simulateStake(ctx, params, acc, stake, validator)
+			return
+		}
+
+		s.stake(ctx, params, acc, stake, validator)
+	})
+}
+
+// skip записывает аккаунт, который не стейкал. Нехватка баланса и отсутствие подходящего
+// валидатора — статус skipped, остальные ошибки (RPC и т.п.) — failed.
+func (s *staker) skip(acc models.Account, validator uint64, err error) {
+	log.Printf("[WARN] skipping %s: %v", acc, err)
+
+	status := journal.StatusFailed
+	if errors.Is(err, errCannotStake) || errors.Is(err, errNoEligibleValidator) {
+		status = journal.StatusSkipped
+	}
+	s.record(journal.Entry{Address: acc.Address, ValidatorID: validator}, status, err)
+}
+
+// stake подписывает, отправляет и дожидается delegate, записывая каждый шаг в журнал запуска.
+func (s *staker) stake(ctx context.Context, cfg RunParams, acc models.Account, stake *big.Int, validator uint64) {
+	entry := journal.Entry{Address: acc.Address, Stake: utils.AmountFromWei(stake), ValidatorID: validator}
+
+	signedTx, plan, err := s.monadClient.SignDelegate(ctx, stake, cfg.ContractAddress, acc.Signer, validator)
+	if err != nil {
+		log.Printf("[WARN] failed stake for %s: %v", acc, err)
+		status := journal.StatusFailed
+		if errors.Is(err, client.ErrInsufficientBalance) {
+			// Комиссия выросла после расчета суммы — аккаунт пропускается, как и при нехватке баланса
+			status = journal.StatusSkipped
+		}
+		s.record(entry, status, err)
+		return
+	}
+
+	entry.Nonce = plan.Nonce
+	entry.TxHash = plan.TxHash.Hex()
+	s.record(entry, journal.StatusSigned, nil)
+
+	if err := s.monadClient.BroadcastTransaction(ctx, signedTx); err != nil {
+		if !errors.Is(err, client.ErrSendUnconfirmed) {
+			log.Printf("[WARN] failed stake for %s: %v", acc, err)
+			s.record(entry, journal.StatusFailed, err)
+			return
+		}
+		// Транзакция могла дойти до сети: повторный delegate стал бы двойным. Ждем ее как отправленную,
+		// а если она так и не попадет в блок, ее перепроверит resume
+		log.Printf("[WARN] stake for %s: %v, waiting for it as sent", acc, err)
+	}
+	s.record(entry, journal.StatusSent, nil)
+
+	onReplace := func(replacement *types.Transaction) {
+		entry.TxHash = replacement.Hash().Hex()
+		s.record(entry, journal.StatusSent, nil)
+	}
+
+	minedTx, err := s.monadClient.WaitForTransaction(ctx, signedTx, acc.Signer, onReplace)
+	if err != nil {
+		log.Printf("[WARN] failed stake for %s: %v", acc, err)
+		if errors.Is(err, client.ErrTxTimeout) || ctx.Err() != nil {
+			// Транзакция может еще попасть в блок — оставляем ее в статусе sent для проверки при перезапуске
+			return
+		}
+		entry.TxHash = minedTx.Hash().Hex()
+		s.record(entry, journal.StatusFailed, err)
+		return
+	}
+
+	entry.TxHash = minedTx.Hash().Hex()
+	s.record(entry, journal.StatusSuccess, nil)
+	log.Printf("[INFO] successfully staked %s MON for %s (validator: %d)", utils.FormatEther(stake), acc, validator)
+}
+
+func (s *staker) simulateStake(ctx context.Context, cfg RunParams, acc models.Account, stake *big.Int, validator uint64) {
+	plan, err := s.monadClient.SimulateDelegate(ctx, stake, cfg.ContractAddress, acc.Signer, validator)
+	if err != nil {
+		log.Printf("[WARN] [DRY-RUN] stake for %s would fail: %v", acc, err)
+		return
+	}
+
+	log.Printf("[INFO] [DRY-RUN] %s: stake %s MON to validator %d | nonce %d | gas limit %d | max fee %s gwei | tip %s gwei | est. cost %s MON",
+		acc,
+		utils.FormatEther(plan.Amount),
+		plan.ValidatorID,
+		plan.Nonce,
+		plan.GasLimit,
+		utils.FormatUnits(plan.MaxFeePerGas, gweiDecimals),
+		utils.FormatUnits(plan.MaxPriorityFeePerGas, gweiDecimals),
+		utils.FormatEther(plan.EstimatedCost),
+	)
+}
+
+// forEachAccount запускает fn для каждого включенного аккаунта в отдельной горутине, выдерживая
+// после аккаунта случайную задержку из его настроек Delay (в dry-run без задержки).
+func (s *staker) forEachAccount(ctx context.Context, cfg RunParams, accounts []models.Account, fn func(acc models.Account)) {
+	accounts = cfg.accountsForRun(accounts)
 
 	for i, acc := range accounts {
 		select {
@@ -47,29 +217,23 @@ func (s *staker) Start(ctx context.Context, cfg RunParams, accounts []models.Acc
 		default:
 		}
 
-		rndStake := utils.RanndomAmount(cfg.Stake.Min, cfg.Stake.Max)
-		validatorID := utils.RandomSliceValue(cfg.Validators)
-
 		s.wg.Add(1)
-		go func(cfg RunParams, acc models.Account, stake float32, validator uint8) {
+		go func(acc models.Account) {
 			defer s.wg.Done()
 
 			select {
 			case <-ctx.Done():
-				log.Printf("[INFO] Context cancelled, skipping transaction for %s", acc.Address.Hex()[:10])
+				log.Printf("[INFO] Context cancelled, skipping transaction for %s", acc)
 				return
 			default:
 			}
 
-			if err := s.monadClient.SendTransaction(ctx, stake, cfg.ContractAddress, acc.PrivateKey, validator); err != nil {
-				log.Printf("[WARN] failed stake for %s: %v", acc.Address.Hex()[:10], err)
-			} else {
-				log.Printf("[INFO] successfully staked %.4f MON for %s (validator: %d)", stake, acc.Address.Hex()[:10], validator)
-			}
-		}(cfg, acc, rndStake, validatorID)
+			fn(acc)
+		}(acc)
 
-		if i < len(accounts)-1 {
-			rndSleep := utils.RanndomAmount(cfg.Delay.Min, cfg.Delay.Max)
+		if i < len(accounts)-1 && !cfg.DryRun {
+			delay := cfg.forAccount(acc).Delay
+			rndSleep := utils.RanndomAmount(delay.Min, delay.Max)
 			log.Printf("[INFO] waiting %.2f seconds before next account...", rndSleep)
 
 			select {
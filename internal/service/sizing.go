@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"ms/internal/models"
+	"ms/pkg/utils"
+)
+
+const (
+	SizingFixed         SizingStrategy = "fixed"
+	SizingRange         SizingStrategy = "range"
+	SizingPercent       SizingStrategy = "percent"
+	SizingAllButReserve SizingStrategy = "allButReserve"
+)
+
+// errCannotStake — баланса аккаунта не хватает на минимальную сумму стейка вместе с комиссией.
+var errCannotStake = errors.New("cannot stake")
+
+// stakeAmount выбирает сумму стейка по стратегии Sizing и обрезает ее до баланса владельца за вычетом
+// оценки комиссии. Оценка уже включает запас на замену зависшей транзакции (client.EstimateDelegateFee).
+// Если после этого сумма меньше минимальной, возвращается errCannotStake с причиной.
+func (s *staker) stakeAmount(ctx context.Context, cfg RunParams, acc models.Account, validator uint64) (*big.Int, error) {
+	minAmount := cfg.Stake.Min.Wei()
+
+	balance, err := s.monadClient.BalanceCheck(ctx, acc.Address)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(minAmount) < 0 || balance.Sign() == 0 {
+		return nil, fmt.Errorf("%w: balance %s MON is below minimum stake %s MON",
+			errCannotStake, formatMON(balance), formatMON(minAmount))
+	}
+
+	// Газ delegate не зависит от суммы — оцениваем его на минимальной сумме, которая точно есть на балансе
+	fee, err := s.monadClient.EstimateDelegateFee(ctx, minAmount, cfg.ContractAddress, acc.Address, validator)
+	if err != nil {
+		return nil, err
+	}
+
+	free := new(big.Int).Sub(balance, fee)
+	if free.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: balance %s MON does not cover max fee %s MON",
+			errCannotStake, formatMON(balance), formatMON(fee))
+	}
+
+	amount, err := desiredAmount(cfg, balance, free)
+	if err != nil {
+		return nil, err
+	}
+	if maxAmount := cfg.Stake.Max.Wei(); cfg.Sizing.Strategy != SizingRange && maxAmount.Sign() > 0 && amount.Cmp(maxAmount) > 0 {
+		amount = maxAmount
+	}
+
+	if amount.Cmp(free) > 0 {
+		log.Printf("[INFO] %s: stake %s MON clamped to %s MON (balance %s MON minus max fee %s MON)",
+			acc, formatMON(amount), formatMON(free), formatMON(balance), formatMON(fee))
+		amount = free
+	}
+
+	if amount.Sign() <= 0 || amount.Cmp(minAmount) < 0 {
+		return nil, fmt.Errorf("%w: %s MON available after max fee %s MON (balance %s MON) is below minimum stake %s MON",
+			errCannotStake, formatMON(amount), formatMON(fee), formatMON(balance), formatMON(minAmount))
+	}
+
+	return amount, nil
+}
+
+// desiredAmount — сумма по стратегии до ограничения балансом; free — баланс за вычетом комиссии.
+func desiredAmount(cfg RunParams, balance, free *big.Int) (*big.Int, error) {
+	switch cfg.Sizing.Strategy {
+	case SizingFixed:
+		return cfg.Sizing.Amount.Wei(), nil
+	case SizingPercent:
+		// Процент в сотых долях, чтобы считать в целых wei; округление, а не отбрасывание: float32 0.29 — это 0.2899999…
+		basisPoints := big.NewInt(int64(math.Round(float64(cfg.Sizing.Percent) * 100)))
+		amount := new(big.Int).Mul(free, basisPoints)
+		return amount.Div(amount, big.NewInt(10000)), nil
+	case SizingAllButReserve:
+		return new(big.Int).Sub(balance, cfg.Sizing.Reserve.Wei()), nil
+	default:
+		return utils.RandomWei(cfg.Stake.Min.Wei(), cfg.Stake.Max.Wei(), cfg.Sizing.Granularity.Wei())
+	}
+}
+
+func formatMON(amount *big.Int) string {
+	return utils.FormatEther(amount)
+}
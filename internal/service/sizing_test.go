@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"ms/internal/models"
+	"ms/pkg/utils"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func mon(s string) utils.Amount {
+	return utils.MustParseAmount(s)
+}
+
+func TestStakeAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance string
+		fee     string
+		stake   AmountRange
+		sizing  Sizing
+		want    string // "" — errCannotStake
+	}{
+		{
+			name:    "fixed fits",
+			balance: "10", fee: "0.01",
+			stake:  AmountRange{Min: mon("1")},
+			sizing: Sizing{Strategy: SizingFixed, Amount: mon("3")},
+			want:   "3",
+		},
+		{
+			name:    "fixed capped by max",
+			balance: "10", fee: "0.01",
+			stake:  AmountRange{Min: mon("1"), Max: mon("2")},
+			sizing: Sizing{Strategy: SizingFixed, Amount: mon("3")},
+			want:   "2",
+		},
+		{
+			name:    "clamped to balance minus fee",
+			balance: "5", fee: "0.01",
+			stake:  AmountRange{Min: mon("1")},
+			sizing: Sizing{Strategy: SizingFixed, Amount: mon("7")},
+			want:   "4.99",
+		},
+		{
+			name:    "clamp below minimum",
+			balance: "1.005", fee: "0.01",
+			stake:  AmountRange{Min: mon("1")},
+			sizing: Sizing{Strategy: SizingFixed, Amount: mon("3")},
+		},
+		{
+			name:    "balance below minimum",
+			balance: "0.5", fee: "0.01",
+			stake:  AmountRange{Min: mon("1")},
+			sizing: Sizing{Strategy: SizingFixed, Amount: mon("3")},
+		},
+		{
+			name:    "balance does not cover fee",
+			balance: "0.01", fee: "0.01",
+			stake:  AmountRange{},
+			sizing: Sizing{Strategy: SizingFixed, Amount: mon("3")},
+		},
+		{
+			name:    "percent of free balance",
+			balance: "100.01", fee: "0.01",
+			stake:  AmountRange{Min: mon("1")},
+			sizing: Sizing{Strategy: SizingPercent, Percent: 29},
+			want:   "29",
+		},
+		{
+			name:    "hundred percent is balance minus fee",
+			balance: "10", fee: "0.01",
+			stake:  AmountRange{Min: mon("1")},
+			sizing: Sizing{Strategy: SizingPercent, Percent: 100},
+			want:   "9.99",
+		},
+		{
+			name:    "percent below minimum",
+			balance: "10", fee: "0.01",
+			stake:  AmountRange{Min: mon("1")},
+			sizing: Sizing{Strategy: SizingPercent, Percent: 5},
+		},
+		{
+			name:    "all but reserve",
+			balance: "10", fee: "0.01",
+			stake:  AmountRange{Min: mon("1")},
+			sizing: Sizing{Strategy: SizingAllButReserve, Reserve: mon("0.5")},
+			want:   "9.5",
+		},
+		{
+			name:    "reserve smaller than fee",
+			balance: "10", fee: "0.01",
+			stake:  AmountRange{Min: mon("1")},
+			sizing: Sizing{Strategy: SizingAllButReserve, Reserve: mon("0.001")},
+			want:   "9.99",
+		},
+		{
+			name:    "reserve above balance",
+			balance: "10", fee: "0.01",
+			stake:  AmountRange{},
+			sizing: Sizing{Strategy: SizingAllButReserve, Reserve: mon("11")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &staker{monadClient: &fakeClient{balance: mon(tt.balance).Wei(), fee: mon(tt.fee).Wei()}}
+			cfg := RunParams{Stake: tt.stake, Sizing: tt.sizing}
+			acc := models.Account{Address: common.HexToAddress("0x1")}
+
+			amount, err := s.stakeAmount(context.Background(), cfg, acc, 1)
+			if tt.want == "" {
+				if !errors.Is(err, errCannotStake) {
+					t.Fatalf("stakeAmount = %v, %v; want errCannotStake", amount, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("stakeAmount: %v", err)
+			}
+			if want := mon(tt.want).Wei(); amount.Cmp(want) != 0 {
+				t.Errorf("stakeAmount = %s MON, want %s MON", formatMON(amount), tt.want)
+			}
+		})
+	}
+}
+
+func TestStakeAmountRange(t *testing.T) {
+	s := &staker{monadClient: &fakeClient{balance: mon("100").Wei(), fee: mon("0.01").Wei()}}
+	cfg := RunParams{
+		Stake:  AmountRange{Min: mon("1"), Max: mon("2")},
+		Sizing: Sizing{Strategy: SizingRange, Granularity: mon("0.25")},
+	}
+	step := mon("0.25").Wei()
+
+	for range 50 {
+		amount, err := s.stakeAmount(context.Background(), cfg, models.Account{}, 1)
+		if err != nil {
+			t.Fatalf("stakeAmount: %v", err)
+		}
+		if amount.Cmp(cfg.Stake.Min.Wei()) < 0 || amount.Cmp(cfg.Stake.Max.Wei()) > 0 || new(big.Int).Mod(amount, step).Sign() != 0 {
+			t.Fatalf("stakeAmount = %s MON, want 1..2 in steps of 0.25", formatMON(amount))
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"ms/internal/client"
+	"ms/internal/models"
+	"ms/pkg/utils"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+const (
+	sweepSwept     = "swept"
+	sweepPending   = "pending"
+	sweepPlanned   = "planned"
+	sweepExcluded  = "excluded"
+	sweepBelowMin  = "below minimum"
+	sweepNoBalance = "balance below fee"
+	sweepFailed    = "failed"
+)
+
+type sweepResult struct {
+	account models.Account
+	amount  *big.Int
+	fee     *big.Int
+	status  string
+}
+
+// Sweep переводит свободный баланс каждого аккаунта (баланс минус максимальная комиссия) на адрес
+// казначейства и печатает в out итоговый отчет. Аккаунты из Exclude и остатки меньше MinAmount не трогаются.
+func (s *staker) Sweep(ctx context.Context, cfg RunParams, sweep SweepParams, accounts []models.Account, out io.Writer) error {
+	log.Printf("[INFO] Sweeping free balances of %d accounts to %s...", len(accounts), sweep.Treasury.Hex())
+	if cfg.DryRun {
+		log.Printf("[INFO] Dry-run mode: transfers are signed and never broadcast")
+	}
+
+	exclude := make(map[string]struct{}, len(sweep.Exclude))
+	for _, e := range sweep.Exclude {
+		exclude[strings.ToLower(e)] = struct{}{}
+	}
+
+	var (
+		mu      sync.Mutex
+		results []sweepResult
+	)
+	report := func(r sweepResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, r)
+	}
+
+	var toSweep []models.Account
+	for _, acc := range cfg.accountsForRun(accounts) {
+		_, byAddress := exclude[strings.ToLower(acc.Address.Hex())]
+		_, byLabel := exclude[strings.ToLower(acc.Label)]
+		if acc.Address == sweep.Treasury || byAddress || (acc.Label != "" && byLabel) {
+			report(sweepResult{account: acc, status: sweepExcluded})
+			continue
+		}
+		toSweep = append(toSweep, acc)
+	}
+
+	s.forEachAccount(ctx, cfg.withDelay(sweep.Delay), toSweep, func(acc models.Account) {
+		report(s.sweepAccount(ctx, cfg, sweep, acc))
+	})
+	s.Wait()
+
+	return printSweepReport(out, results)
+}
+
+func (s *staker) sweepAccount(ctx context.Context, cfg RunParams, sweep SweepParams, acc models.Account) sweepResult {
+	res := sweepResult{account: acc, status: sweepFailed}
+
+	signedTx, plan, err := s.monadClient.SignSweep(ctx, sweep.Treasury, acc.Signer)
+	if errors.Is(err, client.ErrInsufficientBalance) {
+		res.status = sweepNoBalance
+		return res
+	}
+	if err != nil {
+		log.Printf("[WARN] failed to sweep %s: %v", acc, err)
+		return res
+	}
+
+	res.amount, res.fee = plan.Amount, plan.EstimatedCost
+
+	if plan.Amount.Cmp(sweep.MinAmount.Wei()) < 0 {
+		s.monadClient.ReleaseTransaction(signedTx)
+		res.status = sweepBelowMin
+		return res
+	}
+
+	if cfg.DryRun {
+		s.monadClient.ReleaseTransaction(signedTx)
+		res.status = sweepPlanned
+		return res
+	}
+
+	if err := s.monadClient.BroadcastTransaction(ctx, signedTx); err != nil {
+		if !errors.Is(err, client.ErrSendUnconfirmed) {
+			log.Printf("[WARN] failed to sweep %s: %v", acc, err)
+			return res
+		}
+		log.Printf("[WARN] sweeping %s: %v, waiting for it as sent", acc, err)
+	}
+
+	// Перевод отправляет весь баланс за вычетом комиссии: замена с поднятой комиссией не хватило бы
+	// средств, поэтому ждем без замены (signer nil)
+	if _, err := s.monadClient.WaitForTransaction(ctx, signedTx, nil, nil); err != nil {
+		if errors.Is(err, client.ErrTxTimeout) || ctx.Err() != nil {
+			// Перевод может еще попасть в блок — это не отказ
+			log.Printf("[WARN] sweep of %s is still pending: %v", acc, err)
+			res.status = sweepPending
+			return res
+		}
+		log.Printf("[WARN] failed to sweep %s: %v", acc, err)
+		return res
+	}
+
+	log.Printf("[INFO] swept %s MON from %s", utils.FormatEther(plan.Amount), acc)
+	res.status = sweepSwept
+	return res
+}
+
+// printSweepReport печатает результат по каждому аккаунту и итог по статусам.
+func printSweepReport(out io.Writer, results []sweepResult) error {
+	sort.Slice(results, func(i, j int) bool { return results[i].account.Index < results[j].account.Index })
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tACCOUNT\tAMOUNT (MON)\tMAX FEE (MON)\tSTATUS")
+
+	total := new(big.Int)
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.status]++
+		if r.status == sweepSwept || r.status == sweepPlanned {
+			total.Add(total, r.amount)
+		}
+
+		amount, fee := "-", "-"
+		if r.amount != nil {
+			amount, fee = utils.FormatEther(r.amount), utils.FormatEther(r.fee)
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", r.account.Index, r.account.Address.Hex(), amount, fee, r.status)
+	}
+
+	fmt.Fprintf(tw, "TOTAL\t\t%s\t\t\n", utils.FormatEther(total))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var summary []string
+	for _, status := range []string{sweepSwept, sweepPending, sweepPlanned, sweepBelowMin, sweepNoBalance, sweepExcluded, sweepFailed} {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	_, err := fmt.Fprintf(out, "\n%s\n", strings.Join(summary, ", "))
+
+	return err
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"log"
+	"ms/internal/client"
+	consts "ms/internal/client/consts"
+	"ms/internal/models"
+	"ms/pkg/utils"
+	"time"
+)
+
+// Unstake снимает весь стейк аккаунтов со всех валидаторов, которым они делегировали: отправляет undelegate,
+// запоминает withdrawID каждой заявки и делает withdraw, когда проходит задержка эпох.
+func (s *staker) Unstake(ctx context.Context, cfg RunParams, accounts []models.Account) {
+	log.Printf("[INFO] Starting $MON unstaking process for %d accounts...", len(accounts))
+
+	s.forEachAccount(ctx, cfg, accounts, func(acc models.Account) {
+		s.undelegateAccount(ctx, cfg, acc)
+	})
+
+	s.wg.Wait()
+	s.withdrawPending(ctx, cfg)
+}
+
+func (s *staker) undelegateAccount(ctx context.Context, cfg RunParams, acc models.Account) {
+	validators, err := s.monadClient.GetDelegations(ctx, cfg.ContractAddress, acc.Address)
+	if err != nil {
+		log.Printf("[WARN] failed to read delegations of %s: %v", acc, err)
+		return
+	}
+
+	for _, validatorID := range validators {
+		select {
+		case <-ctx.Done():
+			log.Printf("[INFO] Context cancelled, skipping undelegate for %s", acc)
+			return
+		default:
+		}
+
+		delegator, err := s.monadClient.GetDelegator(ctx, cfg.ContractAddress, validatorID, acc.Address)
+		if err != nil {
+			log.Printf("[WARN] failed to read delegation of %s (validator: %d): %v", acc, validatorID, err)
+			continue
+		}
+
+		// Заявки прошлых (в том числе прерванных) запусков ищутся и при нулевом стейке,
+		// иначе после полного undelegate их средства так и остались бы невыведенными
+		free, err := s.collectWithdrawals(ctx, cfg, acc, validatorID)
+		if err != nil {
+			log.Printf("[WARN] failed to read withdrawal requests of %s (validator: %d): %v", acc, validatorID, err)
+			continue
+		}
+
+		if delegator.Stake.Sign() == 0 {
+			continue
+		}
+
+		if free < 0 {
+			log.Printf("[WARN] no free withdraw slot for %s (validator: %d)", acc, validatorID)
+			continue
+		}
+		withdrawID := uint8(free)
+
+		if err := s.monadClient.Undelegate(ctx, delegator.Stake, cfg.ContractAddress, acc.Signer, validatorID, withdrawID); err != nil {
+			log.Printf("[WARN] failed undelegate for %s (validator: %d): %v", acc, validatorID, err)
+			continue
+		}
+
+		log.Printf("[INFO] successfully undelegated %s MON for %s (validator: %d, withdrawID: %d)", utils.FormatEther(delegator.Stake), acc, validatorID, withdrawID)
+
+		pw := pendingWithdrawal{
+			account:     acc,
+			validatorID: validatorID,
+			withdrawID:  withdrawID,
+			amount:      delegator.Stake,
+		}
+
+		if req, err := s.monadClient.GetWithdrawalRequest(ctx, cfg.ContractAddress, validatorID, acc.Address, withdrawID); err == nil {
+			pw.withdrawEpoch = req.WithdrawEpoch
+		} else {
+			log.Printf("[WARN] failed to read withdrawal request of %s: %v", acc, err)
+		}
+
+		s.addPending(pw)
+	}
+}
+
+// withdrawSlot — занятый слот заявки на вывод.
+type withdrawSlot struct {
+	id  uint8
+	req client.WithdrawalRequest
+}
+
+// Сколько пустых слотов подряд после последнего занятого завершают просмотр заявок на вывод.
+// Unstake занимает наименьший свободный слот, поэтому длинные серии пустых слотов между заявками
+// возникают, только если одновременно висело больше withdrawScanGap заявок.
+const withdrawScanGap = 16
+
+// scanWithdrawSlots читает слоты withdrawID по порядку и возвращает занятые слоты и первый
+// свободный (-1, если свободных нет). Слоты переиспользуются, поэтому один пустой слот не конец
+// списка: просмотр останавливается после withdrawScanGap пустых слотов подряд.
+func scanWithdrawSlots(read func(withdrawID uint8) (client.WithdrawalRequest, error)) ([]withdrawSlot, int, error) {
+	var (
+		used  []withdrawSlot
+		free  = -1
+		empty int
+	)
+
+	for id := 0; id <= consts.MaxWithdrawID && empty < withdrawScanGap; id++ {
+		req, err := read(uint8(id))
+		if err != nil {
+			return nil, -1, err
+		}
+
+		if req.WithdrawalAmount.Sign() == 0 {
+			if free < 0 {
+				free = id
+			}
+			empty++
+			continue
+		}
+
+		empty = 0
+
+		used = append(used, withdrawSlot{id: uint8(id), req: req})
+	}
+
+	return used, free, nil
+}
+
+// collectWithdrawals добавляет существующие заявки на вывод пары (аккаунт, валидатор) в список
+// ожидающих withdraw и возвращает первый свободный withdrawID (-1, если свободных нет).
+func (s *staker) collectWithdrawals(ctx context.Context, cfg RunParams, acc models.Account, validatorID uint64) (int, error) {
+	slots, free, err := scanWithdrawSlots(func(withdrawID uint8) (client.WithdrawalRequest, error) {
+		return s.monadClient.GetWithdrawalRequest(ctx, cfg.ContractAddress, validatorID, acc.Address, withdrawID)
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	for _, slot := range slots {
+		if s.isPending(acc, validatorID, slot.id) {
+			continue
+		}
+
+		log.Printf("[INFO] found existing withdrawal request for %s (validator: %d, withdrawID: %d)", acc, validatorID, slot.id)
+		s.addPending(pendingWithdrawal{
+			account:       acc,
+			validatorID:   validatorID,
+			withdrawID:    slot.id,
+			amount:        slot.req.WithdrawalAmount,
+			withdrawEpoch: slot.req.WithdrawEpoch,
+		})
+	}
+
+	return free, nil
+}
+
+func (s *staker) withdrawPending(ctx context.Context, cfg RunParams) {
+	for {
+		remaining := s.pendingList()
+		if len(remaining) == 0 {
+			log.Printf("[INFO] No pending withdrawals left")
+			return
+		}
+
+		epoch, err := s.monadClient.GetEpoch(ctx, cfg.ContractAddress)
+		if err != nil {
+			log.Printf("[WARN] failed to get current epoch: %v", err)
+		} else {
+			for _, pw := range remaining {
+				s.tryWithdraw(ctx, cfg, pw, epoch.Number)
+			}
+		}
+
+		if left := len(s.pendingList()); left > 0 {
+			log.Printf("[INFO] %d withdrawals pending, next epoch check in %.2f seconds...", left, cfg.WithdrawPollInterval)
+
+			select {
+			case <-time.After(time.Duration(cfg.WithdrawPollInterval) * time.Second):
+			case <-ctx.Done():
+				log.Printf("[INFO] Context cancelled, %d withdrawals left pending", left)
+				return
+			}
+		}
+	}
+}
+
+func (s *staker) tryWithdraw(ctx context.Context, cfg RunParams, pw pendingWithdrawal, currentEpoch uint64) {
+	if pw.withdrawEpoch == 0 {
+		req, err := s.monadClient.GetWithdrawalRequest(ctx, cfg.ContractAddress, pw.validatorID, pw.account.Address, pw.withdrawID)
+		if err != nil {
+			log.Printf("[WARN] failed to read withdrawal request of %s: %v", pw.account, err)
+			return
+		}
+
+		pw.withdrawEpoch = req.WithdrawEpoch
+		s.updatePending(pw)
+	}
+
+	if currentEpoch < pw.withdrawEpoch+consts.WithdrawalDelay {
+		return
+	}
+
+	if err := s.monadClient.Withdraw(ctx, cfg.ContractAddress, pw.account.Signer, pw.validatorID, pw.withdrawID); err != nil {
+		log.Printf("[WARN] failed withdraw for %s (validator: %d, withdrawID: %d): %v", pw.account, pw.validatorID, pw.withdrawID, err)
+		return
+	}
+
+	log.Printf("[INFO] successfully withdrew %s MON for %s (validator: %d)", utils.FormatEther(pw.amount), pw.account, pw.validatorID)
+	s.removePending(pw)
+}
+
+func (s *staker) addPending(pw pendingWithdrawal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[pw.account.Address] = append(s.pending[pw.account.Address], pw)
+}
+
+func (s *staker) updatePending(pw pendingWithdrawal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, p := range s.pending[pw.account.Address] {
+		if p.validatorID == pw.validatorID && p.withdrawID == pw.withdrawID {
+			s.pending[pw.account.Address][i] = pw
+		}
+	}
+}
+
+func (s *staker) removePending(pw pendingWithdrawal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := s.pending[pw.account.Address]
+	for i, p := range list {
+		if p.validatorID == pw.validatorID && p.withdrawID == pw.withdrawID {
+			s.pending[pw.account.Address] = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+
+	if len(s.pending[pw.account.Address]) == 0 {
+		delete(s.pending, pw.account.Address)
+	}
+}
+
+func (s *staker) isPending(acc models.Account, validatorID uint64, withdrawID uint8) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.pending[acc.Address] {
+		if p.validatorID == validatorID && p.withdrawID == withdrawID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *staker) pendingList() []pendingWithdrawal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list []pendingWithdrawal
+	for _, pws := range s.pending {
+		list = append(list, pws...)
+	}
+
+	return list
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"math/big"
+	"ms/internal/client"
+	"reflect"
+	"testing"
+)
+
+func TestScanWithdrawSlots(t *testing.T) {
+	tests := []struct {
+		name      string
+		used      []int
+		wantUsed  []uint8
+		wantFree  int
+		wantReads int
+	}{
+		{"no requests", nil, nil, 0, withdrawScanGap},
+		{"compact", []int{0, 1, 2}, []uint8{0, 1, 2}, 3, 3 + withdrawScanGap},
+		{"reused gap", []int{0, 5}, []uint8{0, 5}, 1, 6 + withdrawScanGap},
+		{"gap just below limit", []int{0, withdrawScanGap}, []uint8{0, withdrawScanGap}, 1, withdrawScanGap + 1 + withdrawScanGap},
+		{"gap at limit hides later slots", []int{0, withdrawScanGap + 1}, []uint8{0}, 1, 1 + withdrawScanGap},
+		{"all slots used", allSlots(), allSlotIDs(), -1, 256},
+		{"last slot free", allSlots()[:255], allSlotIDs()[:255], 255, 256},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used := make(map[uint8]bool)
+			for _, id := range tt.used {
+				used[uint8(id)] = true
+			}
+
+			reads := 0
+			slots, free, err := scanWithdrawSlots(func(id uint8) (client.WithdrawalRequest, error) {
+				reads++
+				req := client.WithdrawalRequest{WithdrawalAmount: new(big.Int)}
+				if used[id] {
+					req.WithdrawalAmount.SetInt64(int64(id) + 1)
+				}
+				return req, nil
+			})
+			if err != nil {
+				t.Fatalf("scanWithdrawSlots: %v", err)
+			}
+
+			var ids []uint8
+			for _, slot := range slots {
+				ids = append(ids, slot.id)
+				if slot.req.WithdrawalAmount.Int64() != int64(slot.id)+1 {
+					t.Errorf("slot %d: amount %s", slot.id, slot.req.WithdrawalAmount)
+				}
+			}
+
+			if !reflect.DeepEqual(ids, tt.wantUsed) {
+				t.Errorf("used = %v, want %v", ids, tt.wantUsed)
+			}
+			if free != tt.wantFree {
+				t.Errorf("free = %d, want %d", free, tt.wantFree)
+			}
+			if reads != tt.wantReads {
+				t.Errorf("reads = %d, want %d", reads, tt.wantReads)
+			}
+		})
+	}
+}
+
+func TestScanWithdrawSlotsError(t *testing.T) {
+	errRPC := errors.New("rpc down")
+
+	_, free, err := scanWithdrawSlots(func(id uint8) (client.WithdrawalRequest, error) {
+		if id == 2 {
+			return client.WithdrawalRequest{}, errRPC
+		}
+		return client.WithdrawalRequest{WithdrawalAmount: big.NewInt(1)}, nil
+	})
+	if !errors.Is(err, errRPC) || free != -1 {
+		t.Fatalf("got free %d, err %v; want -1, %v", free, err, errRPC)
+	}
+}
+
+func allSlots() []int {
+	ids := make([]int, 256)
+	for i := range ids {
+		ids[i] = i
+	}
+	return ids
+}
+
+func allSlotIDs() []uint8 {
+	ids := make([]uint8, 256)
+	for i := range ids {
+		ids[i] = uint8(i)
+	}
+	return ids
+}
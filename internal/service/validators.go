@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// CheckValidators сверяет ID валидаторов из конфига и файла аккаунтов с набором валидаторов стейкинг-контракта.
+func (s *staker) CheckValidators(ctx context.Context, cfg RunParams) error {
+	onChain, err := s.monadClient.GetValidatorSet(ctx, cfg.ContractAddress)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[uint64]struct{}, len(onChain))
+	for _, id := range onChain {
+		known[id] = struct{}{}
+	}
+
+	configured := cfg.allValidators()
+
+	var unknown []uint64
+	for _, id := range configured {
+		if _, ok := known[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("validators %v are not registered in the staking contract", unknown)
+	}
+
+	log.Printf("[INFO] All %d configured validators found on-chain (%d registered)", len(configured), len(onChain))
+
+	return nil
+}
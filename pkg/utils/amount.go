@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	EtherDecimals = 18
+	GweiDecimals  = 9
+)
+
+// Amount — точная сумма в MON, хранится в wei. Разбирается из десятичной строки без
+// промежуточного float, поэтому 7.5 в конфиге — это ровно 7500000000000000000 wei.
+type Amount struct {
+	wei *big.Int
+}
+
+// ParseAmount разбирает десятичную сумму в MON, например "7.5" или "0.01".
+func ParseAmount(s string) (Amount, error) {
+	wei, err := ParseEther(s)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	return Amount{wei: wei}, nil
+}
+
+// MustParseAmount — ParseAmount для констант; паникует на некорректной строке.
+func MustParseAmount(s string) Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+func AmountFromWei(wei *big.Int) Amount {
+	if wei == nil {
+		return Amount{}
+	}
+
+	return Amount{wei: new(big.Int).Set(wei)}
+}
+
+// Wei возвращает копию суммы в wei; нулевое значение Amount — 0.
+func (a Amount) Wei() *big.Int {
+	if a.wei == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(a.wei)
+}
+
+func (a Amount) Sign() int {
+	if a.wei == nil {
+		return 0
+	}
+
+	return a.wei.Sign()
+}
+
+func (a Amount) Cmp(b Amount) int {
+	return a.Wei().Cmp(b.Wei())
+}
+
+func (a Amount) String() string {
+	return FormatEther(a.wei)
+}
+
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText разбирает сумму из YAML скаляра (в том числе числа без кавычек) или JSON строки.
+func (a *Amount) UnmarshalText(text []byte) error {
+	parsed, err := ParseAmount(string(text))
+	if err != nil {
+		return err
+	}
+
+	*a = parsed
+	return nil
+}
+
+// UnmarshalJSON принимает и строку, и JSON число: старые журналы хранили сумму числом.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	return a.UnmarshalText(bytes.Trim(data, `"`))
+}
+
+// Gwei — точная цена газа в gwei (комиссии в конфиге), хранится в wei. Как и Amount,
+// разбирается из десятичной строки без float: 0.1 gwei — ровно 100000000 wei.
+type Gwei struct {
+	wei *big.Int
+}
+
+// Wei возвращает копию цены в wei; нулевое значение Gwei — 0.
+func (g Gwei) Wei() *big.Int {
+	if g.wei == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(g.wei)
+}
+
+func (g Gwei) Sign() int {
+	if g.wei == nil {
+		return 0
+	}
+
+	return g.wei.Sign()
+}
+
+func (g Gwei) String() string {
+	return FormatUnits(g.wei, GweiDecimals)
+}
+
+func (g Gwei) MarshalText() ([]byte, error) {
+	return []byte(g.String()), nil
+}
+
+func (g *Gwei) UnmarshalText(text []byte) error {
+	wei, err := ParseUnits(string(text), GweiDecimals)
+	if err != nil {
+		return err
+	}
+
+	g.wei = wei
+	return nil
+}
+
+// ParseEther переводит десятичную сумму в MON в wei.
+func ParseEther(s string) (*big.Int, error) {
+	return ParseUnits(s, EtherDecimals)
+}
+
+// ParseUnits переводит десятичную строку в целое число минимальных единиц с decimals знаками.
+// Экспоненциальная запись и дробная часть длиннее decimals не принимаются.
+func ParseUnits(s string, decimals int) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return nil, fmt.Errorf("некорректная сумма %q", s)
+	}
+	if len(frac) > decimals {
+		return nil, fmt.Errorf("сумма %q: больше %d знаков после точки", s, decimals)
+	}
+
+	digits := whole + frac + strings.Repeat("0", decimals-len(frac))
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("некорректная сумма %q", s)
+	}
+
+	if neg {
+		value.Neg(value)
+	}
+
+	return value, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FormatEther печатает сумму в wei как MON без потери точности и без лишних нулей.
+func FormatEther(wei *big.Int) string {
+	return FormatUnits(wei, EtherDecimals)
+}
+
+// FormatUnits печатает целое число минимальных единиц как десятичную дробь с decimals знаками.
+func FormatUnits(value *big.Int, decimals int) string {
+	if value == nil {
+		return "0"
+	}
+
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(new(big.Int).Abs(value), divisor, new(big.Int))
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+
+	if frac.Sign() == 0 {
+		return sign + whole.String()
+	}
+
+	fracStr := fmt.Sprintf("%0*s", decimals, frac.String())
+	return sign + whole.String() + "." + strings.TrimRight(fracStr, "0")
+}
+
+// RandomWei выбирает случайную сумму из [min, max] с шагом step от min: min, min+step, ... не больше max.
+func RandomWei(min, max, step *big.Int) (*big.Int, error) {
+	if step.Sign() <= 0 {
+		return nil, errors.New("шаг суммы должен быть больше нуля")
+	}
+	if max.Cmp(min) < 0 {
+		return nil, errors.New("максимальная сумма меньше минимальной")
+	}
+
+	steps := new(big.Int).Sub(max, min)
+	steps.Div(steps, step)
+
+	k, err := rand.Int(rand.Reader, steps.Add(steps, big.NewInt(1)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample amount: %w", err)
+	}
+
+	return k.Mul(k, step).Add(k, min), nil
+}
@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func wei(t *testing.T, s string) *big.Int {
+	t.Helper()
+
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("bad test value %q", s)
+	}
+
+	return v
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals int
+		want     string // пусто — ожидается ошибка
+	}{
+		{"1", 18, "1000000000000000000"},
+		{"7.5", 18, "7500000000000000000"},
+		{"0.01", 18, "10000000000000000"},
+		{"0.000000000000000001", 18, "1"},
+		{"1.000000000000000001", 18, "1000000000000000001"},
+		{".5", 18, "500000000000000000"},
+		{"5.", 18, "5000000000000000000"},
+		{"007", 18, "7000000000000000000"},
+		{" 2.5 ", 18, "2500000000000000000"},
+		{"0", 18, "0"},
+		{"-1.5", 18, "-1500000000000000000"},
+		{"-0.000000000000000001", 18, "-1"},
+		{"1.5", 9, "1500000000"},
+		{"0.29", 16, "2900000000000000"},
+
+		{"", 18, ""},
+		{".", 18, ""},
+		{"-", 18, ""},
+		{"-.", 18, ""},
+		{"1.0000000000000000001", 18, ""},
+		{"0.0000000001", 9, ""},
+		{"1e18", 18, ""},
+		{"+1", 18, ""},
+		{"--1", 18, ""},
+		{"1.2.3", 18, ""},
+		{"1,5", 18, ""},
+		{"0x10", 18, ""},
+		{"abc", 18, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUnits(tt.in, tt.decimals)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("ParseUnits(%q, %d) = %s, want error", tt.in, tt.decimals, got)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseUnits(%q, %d): %v", tt.in, tt.decimals, err)
+			continue
+		}
+		if got.Cmp(wei(t, tt.want)) != 0 {
+			t.Errorf("ParseUnits(%q, %d) = %s, want %s", tt.in, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		in       string // пусто — nil
+		decimals int
+		want     string
+	}{
+		{"", 18, "0"},
+		{"0", 18, "0"},
+		{"1", 18, "0.000000000000000001"},
+		{"7500000000000000000", 18, "7.5"},
+		{"1000000000000000000000", 18, "1000"},
+		{"1000000000000000001", 18, "1.000000000000000001"},
+		{"-1500000000000000000", 18, "-1.5"},
+		{"-1", 18, "-0.000000000000000001"},
+		{"1500000000", 9, "1.5"},
+		{"123", 0, "123"},
+	}
+
+	for _, tt := range tests {
+		var in *big.Int
+		if tt.in != "" {
+			in = wei(t, tt.in)
+		}
+
+		if got := FormatUnits(in, tt.decimals); got != tt.want {
+			t.Errorf("FormatUnits(%s, %d) = %q, want %q", tt.in, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "1", "7.5", "0.0001", "-2.25", "123456789.000000000000000001"} {
+		v, err := ParseEther(s)
+		if err != nil {
+			t.Fatalf("ParseEther(%q): %v", s, err)
+		}
+
+		if got := FormatEther(v); got != s {
+			t.Errorf("FormatEther(ParseEther(%q)) = %q", s, got)
+		}
+	}
+}
+
+func TestRandomWei(t *testing.T) {
+	tests := []struct {
+		name           string
+		min, max, step string
+		wantErr        bool
+	}{
+		{"grid", "1000", "2000", "100", false},
+		{"range not a multiple of step", "1000", "2050", "100", false},
+		{"step larger than range", "1000", "1050", "100", false},
+		{"min equals max", "1000", "1000", "1", false},
+		{"zero step", "1000", "2000", "0", true},
+		{"negative step", "1000", "2000", "-1", true},
+		{"max below min", "2000", "1000", "1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			min, max, step := wei(t, tt.min), wei(t, tt.max), wei(t, tt.step)
+
+			for i := 0; i < 200; i++ {
+				got, err := RandomWei(min, max, step)
+				if tt.wantErr {
+					if err == nil {
+						t.Fatalf("RandomWei = %s, want error", got)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("RandomWei: %v", err)
+				}
+
+				if got.Cmp(min) < 0 || got.Cmp(max) > 0 {
+					t.Fatalf("RandomWei = %s, outside [%s, %s]", got, min, max)
+				}
+				if off := new(big.Int).Sub(got, min); new(big.Int).Mod(off, step).Sign() != 0 {
+					t.Fatalf("RandomWei = %s, not on the %s grid from %s", got, step, min)
+				}
+			}
+		})
+	}
+}
+
+func TestAmountUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // пусто — ожидается ошибка
+	}{
+		// Старые журналы хранили сумму JSON числом
+		{`7.5`, "7500000000000000000"},
+		{`0.01`, "10000000000000000"},
+		{`10`, "10000000000000000000"},
+		{`"7.5"`, "7500000000000000000"},
+		{`"0.000000000000000001"`, "1"},
+		{`"abc"`, ""},
+		{`""`, ""},
+	}
+
+	for _, tt := range tests {
+		var a Amount
+		err := json.Unmarshal([]byte(tt.in), &a)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %s, want error", tt.in, a)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if a.Wei().Cmp(wei(t, tt.want)) != 0 {
+			t.Errorf("Unmarshal(%s) = %s wei, want %s", tt.in, a.Wei(), tt.want)
+		}
+	}
+}
+
+func TestAmountJSONRoundTrip(t *testing.T) {
+	type entry struct {
+		Stake Amount `json:"stake"`
+	}
+
+	in := entry{Stake: MustParseAmount("7.000000000000000001")}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"stake":"7.000000000000000001"}` {
+		t.Fatalf("Marshal = %s", data)
+	}
+
+	var out entry
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if out.Stake.Cmp(in.Stake) != 0 {
+		t.Fatalf("round trip = %s, want %s", out.Stake, in.Stake)
+	}
+
+	var zero Amount
+	if zero.String() != "0" || zero.Sign() != 0 || zero.Wei().Sign() != 0 {
+		t.Fatalf("zero Amount = %q", zero.String())
+	}
+}
+
+func TestGweiUnmarshalText(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"0.1", "100000000", false},
+		{"200.0", "200000000000", false},
+		{"1.000000001", "1000000001", false},
+		{"0", "0", false},
+		{"1.0000000001", "", true},
+		{"1e9", "", true},
+	}
+
+	for _, tt := range tests {
+		var g Gwei
+		err := g.UnmarshalText([]byte(tt.in))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("UnmarshalText(%q) = %s wei, want error", tt.in, g.Wei())
+			}
+			continue
+		}
+		if err != nil || g.Wei().String() != tt.want {
+			t.Errorf("UnmarshalText(%q) = %s wei, %v; want %s", tt.in, g.Wei(), err, tt.want)
+		}
+	}
+}
@@ -15,9 +15,14 @@ import (
 
 var rgx = regexp.MustCompile(`^[0-9a-fA-F]+$`).MatchString
 
+var (
+	ErrKeyLength = errors.New("invalid private key: incorrect length")
+	ErrKeyNotHex = errors.New("invalid private key: contains non-hexadecimal characters")
+)
+
 func ParsePrivateKey(hexKey string) (*ecdsa.PrivateKey, error) {
 	if len(hexKey) != 64 && len(hexKey) != 66 {
-		return nil, errors.New("invalid private key: incorrect length")
+		return nil, ErrKeyLength
 	}
 
 	if len(hexKey) > 2 && hexKey[:2] == "0x" {
@@ -25,7 +30,7 @@ func ParsePrivateKey(hexKey string) (*ecdsa.PrivateKey, error) {
 	}
 
 	if !rgx(hexKey) {
-		return nil, errors.New("invalid private key: contains non-hexadecimal characters")
+		return nil, ErrKeyNotHex
 	}
 
 	privateKeyBytes, err := hex.DecodeString(hexKey)
@@ -34,7 +39,7 @@ func ParsePrivateKey(hexKey string) (*ecdsa.PrivateKey, error) {
 	}
 
 	if len(privateKeyBytes) != 32 {
-		return nil, errors.New("invalid private key format: incorrect byte length (must be 32 bytes)")
+		return nil, fmt.Errorf("%w (must be 32 bytes)", ErrKeyLength)
 	}
 
 	privateKey, err := crypto.ToECDSA(privateKeyBytes)